@@ -0,0 +1,98 @@
+package mockfactory
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/maksemen2/mockfactory/internal/generator"
+)
+
+// assign stores a generated value into target.
+// Generated values are records for structs, []any for slices and arrays, generator.Bytes for bytes,
+// []generator.MapEntry for maps, and nil for nil pointers.
+func assign(target reflect.Value, value any) error {
+	if value == nil {
+		target.SetZero()
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(target.Type().Elem())
+		if err := assign(elem.Elem(), value); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+
+	case reflect.Struct:
+		record, ok := value.(*generator.Record)
+		if !ok {
+			break // registered struct types, like time.Time, are generated directly
+		}
+		for _, field := range record.Fields {
+			fieldValue := target.FieldByName(field.Field.Name)
+			if !fieldValue.IsValid() || !fieldValue.CanSet() {
+				continue // unexported fields can not be populated
+			}
+			if err := assign(fieldValue, field.Value); err != nil {
+				return fmt.Errorf("field %s: %w", field.Field.Name, err)
+			}
+		}
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		if bytes, ok := value.(generator.Bytes); ok {
+			if target.Kind() == reflect.Slice {
+				target.Set(reflect.ValueOf(bytes.Data).Convert(target.Type()))
+			} else {
+				reflect.Copy(target, reflect.ValueOf(bytes.Data))
+			}
+			return nil
+		}
+		elems, ok := value.([]any)
+		if !ok {
+			break // registered types, like uuid.UUID, are generated directly
+		}
+		if target.Kind() == reflect.Slice {
+			target.Set(reflect.MakeSlice(target.Type(), len(elems), len(elems)))
+		}
+		for i, elem := range elems {
+			if i >= target.Len() {
+				break
+			}
+			if err := assign(target.Index(i), elem); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		entries, ok := value.([]generator.MapEntry)
+		if !ok {
+			break
+		}
+		result := reflect.MakeMapWithSize(target.Type(), len(entries))
+		for _, entry := range entries {
+			key := reflect.New(target.Type().Key()).Elem()
+			if err := assign(key, entry.Key); err != nil {
+				return fmt.Errorf("map key: %w", err)
+			}
+			elem := reflect.New(target.Type().Elem()).Elem()
+			if err := assign(elem, entry.Value); err != nil {
+				return fmt.Errorf("map value: %w", err)
+			}
+			result.SetMapIndex(key, elem)
+		}
+		target.Set(result)
+		return nil
+	}
+
+	// scalars and registered types, converted for named types like "type Status string"
+	v := reflect.ValueOf(value)
+	if !v.Type().ConvertibleTo(target.Type()) {
+		return fmt.Errorf("can not assign %T to %s", value, target.Type())
+	}
+	target.Set(v.Convert(target.Type()))
+	return nil
+}
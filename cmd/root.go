@@ -4,19 +4,31 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/maksemen2/mockfactory/internal/config"
 	"github.com/maksemen2/mockfactory/pkg"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "mockfactory",
 	Short: "Generate mock data from Go structs",
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg, logger, _ := ExtractConfig(cmd)
+	// errors are printed by Execute, usage is printed only for unknown or malformed flags
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cfg, logger, err := ExtractConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
 		logger.Debug("Initializing process", "config", cfg)
-		pkg.GenerateFromFile(cfg, logger)
+		return pkg.GenerateFromFile(cfg, logger)
 	},
 }
 
@@ -28,16 +40,27 @@ func Execute() {
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringP("input", "i", "", "Path to input Go file (required)")
+	rootCmd.PersistentFlags().StringP("input", "i", "", "Path to input Go file, package directory or pattern like ./... (required)")
 	rootCmd.MarkPersistentFlagRequired("input")
 	rootCmd.PersistentFlags().StringSlice("structs", []string{}, "Comma-separated list of struct names")
 	rootCmd.PersistentFlags().Int("count", 1, "Number of objects to generate per struct")
 	rootCmd.PersistentFlags().Int64("seed", 0, "Random seed (default time.Now().UnixNano())")
-	rootCmd.PersistentFlags().String("format", "json", "Output format")
+	rootCmd.PersistentFlags().Int("max-depth", 5, "Maximum depth of nested structs")
+	rootCmd.PersistentFlags().Float64("nullable", 0, "Default probability of nil pointers, from 0 to 1")
+	rootCmd.PersistentFlags().String("format", "json", "Output format: json|ndjson|yaml|toml|csv|tsv|sql|go")
+	rootCmd.PersistentFlags().String("now", "", "Reference time of time ranges as RFC3339 (default time.Now())")
+	rootCmd.PersistentFlags().String("locale", "en_US", "Locale of names, addresses, phones, postcodes and dates: en_US|de_DE|ru_RU|fr_FR|ja_JP")
+	rootCmd.PersistentFlags().Bool("json-compact", false, "Write JSON without indentation")
+	rootCmd.PersistentFlags().String("sql-dialect", "postgres", "SQL dialect: postgres|mysql|sqlite")
+	rootCmd.PersistentFlags().Int("sql-batch", 100, "Maximum number of rows per SQL INSERT statement")
+	rootCmd.PersistentFlags().String("csv-nested", "flatten", "CSV output of nested structs: flatten|json")
 	rootCmd.PersistentFlags().StringP("output", "o", ".", "Output path")
 	rootCmd.PersistentFlags().String("strategy", "per-struct", "Output strategy: per-struct|single-file")
+	rootCmd.PersistentFlags().String("layout", "keyed", "Top-level layout of single-file output: keyed|list")
 	rootCmd.PersistentFlags().String("template", "", "File name template (e.g. {struct}_{count}.json)")
 	rootCmd.PersistentFlags().String("ignore", "all", "Ignore strategy: untagged|with-tag|all|none")
+	rootCmd.PersistentFlags().Bool("no-heuristics", false, "Do not choose generators of untagged fields by their names")
+	rootCmd.PersistentFlags().String("rules", "", "Path to a YAML file with rules for untagged fields")
 	rootCmd.PersistentFlags().String("log-level", "", "Log level: debug|info|warn|error")
 }
 
@@ -65,11 +88,57 @@ func ExtractConfig(cmd *cobra.Command) (*config.Config, *slog.Logger, error) {
 		return nil, nil, err
 	}
 
+	cfg.Generation.MaxDepth, err = cmd.Flags().GetInt("max-depth")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg.Generation.Nullable, err = cmd.Flags().GetFloat64("nullable")
+	if err != nil {
+		return nil, nil, err
+	}
+
 	cfg.Generation.Format, err = cmd.Flags().GetString("format")
 	if err != nil {
 		return nil, nil, err
 	}
 
+	cfg.Generation.Locale, err = cmd.Flags().GetString("locale")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now, err := cmd.Flags().GetString("now")
+	if err != nil {
+		return nil, nil, err
+	}
+	if now != "" {
+		cfg.Generation.Now, err = time.Parse(time.RFC3339Nano, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid now: %w", err)
+		}
+	}
+
+	cfg.JSON.Compact, err = cmd.Flags().GetBool("json-compact")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg.SQL.Dialect, err = cmd.Flags().GetString("sql-dialect")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg.SQL.BatchSize, err = cmd.Flags().GetInt("sql-batch")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg.CSV.Nested, err = cmd.Flags().GetString("csv-nested")
+	if err != nil {
+		return nil, nil, err
+	}
+
 	cfg.Output.Path, err = cmd.Flags().GetString("output")
 	if err != nil {
 		return nil, nil, err
@@ -88,6 +157,11 @@ func ExtractConfig(cmd *cobra.Command) (*config.Config, *slog.Logger, error) {
 		return nil, nil, fmt.Errorf("invalid file strategy: %s", strategy)
 	}
 
+	cfg.Output.Layout, err = cmd.Flags().GetString("layout")
+	if err != nil {
+		return nil, nil, err
+	}
+
 	cfg.Output.FileNameTemplate, err = cmd.Flags().GetString("template")
 	if err != nil {
 		return nil, nil, err
@@ -110,6 +184,22 @@ func ExtractConfig(cmd *cobra.Command) (*config.Config, *slog.Logger, error) {
 		return nil, nil, fmt.Errorf("invalid ignore strategy: %s", ignore)
 	}
 
+	cfg.Fields.DisableHeuristics, err = cmd.Flags().GetBool("no-heuristics")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rulesPath, err := cmd.Flags().GetString("rules")
+	if err != nil {
+		return nil, nil, err
+	}
+	if rulesPath != "" {
+		cfg.Fields.Rules, err = loadFieldRules(rulesPath)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	logLevel, err := cmd.Flags().GetString("log-level")
 	if err != nil {
 		return nil, nil, err
@@ -122,6 +212,28 @@ func ExtractConfig(cmd *cobra.Command) (*config.Config, *slog.Logger, error) {
 	return cfg, logger, nil
 }
 
+// loadFieldRules reads a YAML list of rules for untagged fields, e.g.
+//
+//   - name: ^sku$
+//     types: [string]
+//     tags: prefix=SKU-;len=6
+func loadFieldRules(path string) ([]config.FieldRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []config.FieldRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	for _, rule := range rules {
+		if _, err := regexp.Compile(rule.Name); err != nil {
+			return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+		}
+	}
+	return rules, nil
+}
+
 func getLogLevel(str string) slog.Level {
 	switch str {
 	case "debug":
@@ -0,0 +1,111 @@
+package root
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// execute runs the root command with the arguments and the default values of the other flags.
+// It returns the names of the files written to the output directory and the error.
+func execute(t *testing.T, source string, args ...string) ([]string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	input := filepath.Join(dir, "models.go")
+	if err := os.WriteFile(input, []byte(source), 0o644); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+	output := filepath.Join(dir, "out")
+	if err := os.Mkdir(output, 0o755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+	rootCmd.SetArgs(append([]string{"--input", input, "--output", output, "--log-level", "error"}, args...))
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull) // logs
+	defer func() { os.Stdout = stdout }()
+	err := rootCmd.Execute()
+
+	entries, _ := os.ReadDir(output)
+	var files []string
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+	return files, err
+}
+
+const userSource = `package models
+
+type User struct {
+	ID   int    ` + "`mock:\"min=1;max=100\"`" + `
+	Name string ` + "`mock:\"len=5\"`" + `
+}
+`
+
+func TestRootCmd(t *testing.T) {
+	files, err := execute(t, userSource, "--count", "3")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != "User.json" {
+		t.Errorf("Output files = %v, want User.json", files)
+	}
+}
+
+func TestRootCmd_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"layout", []string{"--strategy", "single-file", "--layout", "bogus"}, "Output.Layout must be one of keyed list"},
+		{"nullable above 1", []string{"--nullable", "7"}, "Generation.Nullable must be at most 1"},
+		{"negative nullable", []string{"--nullable", "-0.5"}, "Generation.Nullable must be at least 0"},
+		{"csv nested", []string{"--format", "csv", "--csv-nested", "bogus"}, "CSV.Nested must be one of flatten json"},
+		{"locale", []string{"--locale", "xx_XX"}, "Generation.Locale must be one of en_US de_DE ru_RU fr_FR ja_JP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := execute(t, userSource, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+			if len(files) != 0 {
+				t.Errorf("Output files = %v, want none", files)
+			}
+		})
+	}
+}
+
+func TestRootCmd_InvalidTag(t *testing.T) {
+	source := `package models
+
+type User struct {
+	ID   int    ` + "`mock:\"min=1;max=100\"`" + `
+	Name string ` + "`mock:\"kind=full_name\"`" + `
+}
+`
+	files, err := execute(t, source, "--count", "3")
+	if err == nil || !strings.Contains(err.Error(), "field Name: invalid mock tags") {
+		t.Errorf("Execute() error = %v, want invalid mock tags of field Name", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Output files = %v, want none", files)
+	}
+}
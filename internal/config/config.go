@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type OutputStrategy int
 
 const (
@@ -7,6 +9,12 @@ const (
 	SingleFile                          // Writes all structs to single file
 )
 
+// Top-level layouts of single file output
+const (
+	KeyedLayout = "keyed" // Instances keyed by struct names, e.g. {"User": [...]}
+	ListLayout  = "list"  // List of struct names with their instances, e.g. [{"name": "User", "items": [...]}]
+)
+
 type FieldIgnoreStrategy int
 
 const (
@@ -17,28 +25,59 @@ const (
 )
 
 type Config struct {
-	InputPath  string           `validate:"required,input_file"` // Path to input file.
+	InputPath  string           `validate:"required,input_path"` // Path to input file, package directory or package pattern (e.g. ./...).
 	Generation GenerationConfig `validate:"required"`
 	Output     OutputConfig     `validate:"required"`
 	Fields     FieldsConfig     `validate:"required"`
+	JSON       JSONConfig
+	SQL        SQLConfig
+	CSV        CSVConfig
 	Logging    LoggingConfig
 }
 
 type GenerationConfig struct {
-	StructNames []string // Names of structs to generate. If empty, all structs will be generated
-	Count       int      `validate:"min=1"` // Count of mocks to generate per struct
-	RandSeed    int64    // Seed for random values
-	Format      string   `validate:"oneof=json"` // Format of output file. Currently only JSON is supported
+	StructNames []string  // Names of structs to generate. If empty, all structs will be generated
+	Count       int       `validate:"min=1"` // Count of mocks to generate per struct
+	RandSeed    int64     // Seed for random values
+	Now         time.Time // Reference time of time ranges like "past" and "-30d". The current time if zero
+	MaxDepth    int       `validate:"min=0"`                                         // Maximum depth of nested structs. Deeper structs are left empty
+	Nullable    float64   `validate:"min=0,max=1"`                                   // Default probability of generating nil pointers
+	Format      string    `validate:"oneof=json ndjson yaml toml csv tsv sql go"`    // Format of output files: json, ndjson, yaml, toml, csv, tsv, sql or go
+	Locale      string    `validate:"omitempty,oneof=en_US de_DE ru_RU fr_FR ja_JP"` // Default locale of names, addresses, phones, postcodes and dates
 }
 
 type OutputConfig struct {
-	Path             string         `validate:"required"`               // Path to write output files. Can be a file path or folder
-	OutputStrategy   OutputStrategy `validate:"required,file_strategy"` // Strategy for writing output files
-	FileNameTemplate string         `validate:"file_name_template"`     // Template for file names. Can contain the following placeholders: {struct} - struct name, {count} - count of mocks
+	Path             string         `validate:"required"`           // Path to write output files. Can be a file path or folder
+	OutputStrategy   OutputStrategy `validate:"file_strategy"`      // Strategy for writing output files
+	Layout           string         `validate:"oneof=keyed list"`   // Top-level layout of single file output: keyed or list
+	FileNameTemplate string         `validate:"file_name_template"` // Template for file names. Can contain the following placeholders: {struct} - struct name, {count} - count of mocks
 }
 
 type FieldsConfig struct {
-	IgnoreStrategy FieldIgnoreStrategy `validate:"required,ignore_strategy"`
+	IgnoreStrategy    FieldIgnoreStrategy `validate:"ignore_strategy"`
+	DisableHeuristics bool                // Do not choose mock tags of untagged fields by their names and types
+	Rules             []FieldRule         `validate:"dive"` // Rules for untagged fields, checked before the built-in ones
+}
+
+// FieldRule chooses mock tags of untagged fields by their names and types.
+type FieldRule struct {
+	Name          string   `yaml:"name" validate:"required"` // Regular expression matched against lower cased field names without underscores, e.g. "^email$"
+	CaseSensitive bool     `yaml:"case_sensitive"`           // Match Name against field names as declared, e.g. to find word boundaries like "UserID"
+	Types         []string `yaml:"types"`                    // Patterns of type kinds or qualified names, e.g. "string", "int*" or "time.Time". Any type if empty
+	Tags          string   `yaml:"tags" validate:"required"` // Mock tags of matching fields, e.g. "kind=email"
+}
+
+type JSONConfig struct {
+	Compact bool // Write JSON without indentation
+}
+
+type SQLConfig struct {
+	Dialect   string `validate:"oneof=postgres mysql sqlite"` // SQL dialect of INSERT statements
+	BatchSize int    `validate:"min=1"`                       // Maximum number of rows per INSERT statement
+}
+
+type CSVConfig struct {
+	Nested string `validate:"oneof=flatten json"` // Output of nested structs: dotted columns (flatten) or JSON cells (json)
 }
 
 type LoggingConfig struct {
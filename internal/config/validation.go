@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -13,7 +14,7 @@ func (c *Config) Validate() error {
 
 	validate.RegisterValidation("file_strategy", validateFileStrategy)
 	validate.RegisterValidation("ignore_strategy", validateIgnoreStrategy)
-	validate.RegisterValidation("input_file", validateInputFile)
+	validate.RegisterValidation("input_path", validateInputPath)
 	validate.RegisterValidation("file_name_template", validateFileNameTemplate)
 
 	if err := validate.Struct(c); err != nil {
@@ -28,9 +29,7 @@ func (c *Config) Validate() error {
 }
 
 func validateFileStrategy(fl validator.FieldLevel) bool {
-	fmt.Println("validateFileStrategy")
 	value, ok := fl.Field().Interface().(OutputStrategy)
-	fmt.Println(value)
 	if !ok {
 		return false
 	}
@@ -45,13 +44,20 @@ func validateIgnoreStrategy(fl validator.FieldLevel) bool {
 	return value >= IgnoreUntagged && value <= IncludeAll
 }
 
-func validateInputFile(fl validator.FieldLevel) bool {
-	filePath := fl.Field().String()
-	info, err := os.Stat(filePath)
-	if err != nil {
-		return false
+// validateInputPath accepts an existing file or directory, recursive package patterns like "./..."
+// and import paths like "example.com/models", which are resolved when packages are loaded.
+func validateInputPath(fl validator.FieldLevel) bool {
+	path := fl.Field().String()
+	if strings.HasSuffix(path, "...") {
+		path = strings.TrimSuffix(strings.TrimSuffix(path, "..."), "/")
+		if path == "" {
+			return true
+		}
+	}
+	if _, err := os.Stat(path); err == nil {
+		return true
 	}
-	return !info.IsDir()
+	return !filepath.IsAbs(path) && !strings.HasPrefix(path, ".") && !strings.HasSuffix(path, ".go")
 }
 
 func validateFileNameTemplate(fl validator.FieldLevel) bool {
@@ -62,6 +68,12 @@ func validateFileNameTemplate(fl validator.FieldLevel) bool {
 	return strings.Contains(value, "{struct}")
 }
 
+// fieldName returns the path of the invalid field within the config, like "Output.Layout".
+func fieldName(e validator.FieldError) string {
+	_, name, _ := strings.Cut(e.StructNamespace(), ".")
+	return name
+}
+
 func wrapValidationErrors(err error) error {
 	if _, ok := err.(*validator.InvalidValidationError); ok {
 		return err
@@ -71,21 +83,23 @@ func wrapValidationErrors(err error) error {
 	for _, e := range err.(validator.ValidationErrors) {
 		switch e.Tag() {
 		case "required":
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is required", e.Field()))
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s is required", fieldName(e)))
 		case "min":
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at least %s", e.Field(), e.Param()))
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at least %s", fieldName(e), e.Param()))
+		case "max":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at most %s", fieldName(e), e.Param()))
 		case "oneof":
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be one of %s", e.Field(), e.Param()))
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be one of %s", fieldName(e), e.Param()))
 		case "file_strategy":
-			errMsgs = append(errMsgs, fmt.Sprintf("invalid file strategy in field %s", e.Field()))
+			errMsgs = append(errMsgs, fmt.Sprintf("invalid file strategy in field %s", fieldName(e)))
 		case "ignore_strategy":
-			errMsgs = append(errMsgs, fmt.Sprintf("invalid ignore strategy in field %s", e.Field()))
-		case "input_file":
-			errMsgs = append(errMsgs, fmt.Sprintf("invalid input file path: %s", e.Value()))
+			errMsgs = append(errMsgs, fmt.Sprintf("invalid ignore strategy in field %s", fieldName(e)))
+		case "input_path":
+			errMsgs = append(errMsgs, fmt.Sprintf("invalid input path: %s", e.Value()))
 		case "file_name_template":
 			errMsgs = append(errMsgs, "file name template should contain {struct} placeholder")
 		default:
-			errMsgs = append(errMsgs, fmt.Sprintf("validation failed for field %s", e.Field()))
+			errMsgs = append(errMsgs, fmt.Sprintf("validation failed for field %s", fieldName(e)))
 		}
 	}
 
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+)
+
+const defaultProbability = 0.5
+
+// BoolGenerator generates booleans
+// that are true with the given probability.
+type BoolGenerator struct {
+	probability float64
+	BaseGenerator
+}
+
+// NewBoolGenerator creates a new BoolGenerator using the "probability" tag,
+// the probability of true from 0 to 1. Defaults to 0.5 if not provided.
+func NewBoolGenerator(tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[bool] {
+	probability := defaultProbability
+	if value, ok := tags["probability"]; ok {
+		p, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			logger.Error("Failed to parse probability tag", "probability", value, "error", err)
+			panic(err)
+		}
+		if p < 0 || p > 1 {
+			logger.Error("Invalid probability provided", "probability", p)
+			panic(fmt.Sprintf("invalid probability provided: %v", p))
+		}
+		probability = p
+	}
+
+	logger.Debug("BoolGenerator created", "probability", probability)
+	return &BoolGenerator{probability, BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns true with the configured probability.
+func (g *BoolGenerator) Evaluate() (bool, error) {
+	result := g.rand.Float64() < g.probability
+	g.logger.Debug("Evaluate generated value", "value", result)
+	return result, nil
+}
+
+func (g *BoolGenerator) Validate() error {
+	return nil
+}
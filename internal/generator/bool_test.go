@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+)
+
+func TestBoolGenerator_Probability(t *testing.T) {
+	g := NewBoolGenerator(map[string]string{"probability": "0.8"}, testRand(), testutils.TestLogger())
+	trues := 0
+	for range 10000 {
+		if val, _ := g.Evaluate(); val {
+			trues++
+		}
+	}
+	if trues < 7500 || trues > 8500 {
+		t.Errorf("Expected about 8000 trues, got %d", trues)
+	}
+}
+
+func TestBoolGenerator_InvalidProbability(t *testing.T) {
+	for _, probability := range []string{"1.5", "-0.1", "often"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for probability %q", probability)
+				}
+			}()
+			NewBoolGenerator(map[string]string{"probability": probability}, testRand(), testutils.TestLogger())
+		}()
+	}
+}
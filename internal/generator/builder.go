@@ -0,0 +1,363 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+var (
+	ErrUnknownType = errors.New("unknown generator type provided")
+	ErrInvalidTags = errors.New("invalid mock tags")
+)
+
+// Options configure generators created by a Builder.
+type Options struct {
+	MaxDepth int       // Maximum depth of nested structs. Structs nested deeper are left empty
+	Nullable float64   // Default probability of generating nil pointers
+	Locale   string    // Default locale of string kinds, e.g. "de_DE". DefaultLocale if empty
+	Now      time.Time // Reference time of time ranges. The current time if zero
+	Refs     *RefPool  // Values of fields referenced by "ref" tags. The tags are ignored if nil
+}
+
+// Builder creates generators for type descriptors,
+// recursing into composite types like structs and pointers.
+// Every generator gets its own random stream derived from its path,
+// e.g. "User", "Address", "City", so generated values are reproducible with a seed.
+type Builder struct {
+	options Options
+	source  *RandSource
+	logger  *slog.Logger
+	stack   []*typeinfo.Type // structs currently being built, recursive types repeat until MaxDepth
+	path    []string         // path of the generator currently being built
+	locale  string           // locale of the type currently being built, inherited from enclosing "locale" tags
+}
+
+// NewBuilder creates a new Builder.
+func NewBuilder(options Options, source *RandSource, logger *slog.Logger) *Builder {
+	return &Builder{options: options, source: source, logger: logger}
+}
+
+// Build returns a generator for values of the given type configured by mock tags.
+// Invalid tags are reported as ErrInvalidTags.
+func (b *Builder) Build(t *typeinfo.Type, tags map[string]string) (_ AnyGenerator, err error) {
+	defer recoverTags(&err)
+	root := t.Name
+	if root == "" {
+		root = t.String()
+	}
+	b.path = []string{root}
+	b.locale = b.options.Locale
+	if err := checkLocale(b.locale); err != nil {
+		b.logger.Error("Unknown default locale provided", "locale", b.locale)
+		return nil, err
+	}
+	return b.build(t, tags)
+}
+
+// recoverTags turns a panic of a generator constructor on invalid tags into an error.
+func recoverTags(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if cause, ok := r.(error); ok {
+		*err = fmt.Errorf("%w: %w", ErrInvalidTags, cause)
+	} else {
+		*err = fmt.Errorf("%w: %v", ErrInvalidTags, r)
+	}
+}
+
+// buildAt builds a generator for a part of the current type, like a field or a slice element.
+func (b *Builder) buildAt(part string, t *typeinfo.Type, tags map[string]string) (AnyGenerator, error) {
+	b.path = append(b.path, part)
+	defer func() { b.path = b.path[:len(b.path)-1] }()
+	return b.build(t, tags)
+}
+
+// rand returns the random stream of the generator currently being built.
+func (b *Builder) rand() *rand.Rand {
+	return b.source.Rand(b.path...)
+}
+
+func (b *Builder) build(t *typeinfo.Type, tags map[string]string) (AnyGenerator, error) {
+	b.logger.Debug("Building generator", "type", t, "path", strings.Join(b.path, "."), "mockTags", tags)
+
+	// a "locale" tag applies to the whole value, e.g. to all fields of a nested struct
+	if locale, ok := tags["locale"]; ok {
+		if err := checkLocale(locale); err != nil {
+			b.logger.Error("Unknown locale provided", "locale", locale)
+			return nil, err
+		}
+		defer func(outer string) { b.locale = outer }(b.locale)
+		b.locale = locale
+	}
+
+	if _, ok := tags["ref"]; ok && b.options.Refs != nil && t.Kind != typeinfo.Pointer {
+		return b.buildRef(t, tags)
+	}
+	if oneof, ok := tags["oneof"]; ok && t.Kind.IsBasic() {
+		values, weights := parseOneof(oneof)
+		return &GenericGenerator[any]{impl: NewOneofGenerator(t.Kind, values, weights, b.rand(), b.logger)}, nil
+	}
+	if isEnum(t, tags) {
+		return &GenericGenerator[any]{impl: NewOneofGenerator(t.Kind, t.Enum, nil, b.rand(), b.logger)}, nil
+	}
+
+	if factory, ok := LookupFactory(t); ok {
+		return factory.Create(b.contextual(tags), b.rand(), b.logger), nil
+	}
+
+	switch t.Kind {
+	case typeinfo.Struct:
+		return b.buildStruct(t)
+	case typeinfo.Pointer:
+		return b.buildPointer(t, tags)
+	case typeinfo.Slice, typeinfo.Array:
+		return b.buildSlice(t, tags)
+	case typeinfo.Map:
+		return b.buildMap(t, tags)
+	}
+
+	b.logger.Debug("Unknown generator type provided", "type", t)
+	return nil, fmt.Errorf("%w: %s", ErrUnknownType, t)
+}
+
+func (b *Builder) buildStruct(t *typeinfo.Type) (AnyGenerator, error) {
+	// the top-level struct is not counted as nested,
+	// recursive types like "Parent *Node" expand level by level until the limit
+	if len(b.stack) > b.options.MaxDepth {
+		b.logger.Debug("Max depth reached, leaving struct empty", "type", t, "maxDepth", b.options.MaxDepth)
+		return &NilGenerator{}, nil
+	}
+
+	b.stack = append(b.stack, t)
+	defer func() { b.stack = b.stack[:len(b.stack)-1] }()
+
+	fields := make([]FieldGenerator, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		gen, err := b.buildField(field)
+		if errors.Is(err, ErrUnknownType) {
+			// fields like channels, functions and interfaces keep their zero value
+			b.logger.Debug("Unsupported field type, leaving field empty", "field", field.Name, "type", field.Type, "error", err)
+			gen, err = &NilGenerator{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		fields = append(fields, FieldGenerator{field, gen})
+	}
+	deps, err := fieldDeps(fields)
+	if err != nil {
+		b.logger.Error("Failed to resolve field references", "type", t, "error", err)
+		return nil, err
+	}
+	order, err := fieldOrder(fields, deps)
+	if err != nil {
+		b.logger.Error("Failed to order struct fields", "type", t, "error", err)
+		return nil, err
+	}
+	unique := uniqueGroups(fields, deps, order)
+
+	return &GenericGenerator[*Record]{impl: NewStructGenerator(t, fields, order, unique, b.options.Refs, b.rand(), b.logger)}, nil
+}
+
+// buildField builds a generator for a struct field.
+// Values of fields with "expr" or "template" tags are derived from other fields,
+// comparison tags like "after=CreatedAt" constrain the generated values.
+// The "unique" tag is handled by the struct generator.
+// Invalid tags are reported as errors of the field.
+func (b *Builder) buildField(field typeinfo.Field) (_ AnyGenerator, err error) {
+	defer recoverTags(&err)
+	tags, relations := splitRelationTags(field.MockTags)
+	if _, ok := tags["unique"]; ok {
+		delete(tags, "unique") // own is a copy of the field tags
+	}
+	if relations == nil {
+		return b.buildAt(field.Name, field.Type, tags)
+	}
+
+	b.path = append(b.path, field.Name)
+	defer func() { b.path = b.path[:len(b.path)-1] }()
+
+	// derived values of pointer fields are never nil unless a referenced field is nil
+	t := field.Type
+	for t.Kind == typeinfo.Pointer {
+		t = t.Elem
+	}
+	expr, isExpr := relations["expr"]
+	tmpl, isTemplate := relations["template"]
+	if isExpr && isTemplate {
+		b.logger.Error("Both expr and template tags provided", "expr", expr, "template", tmpl)
+		panic("expr and template tags can not be combined")
+	}
+
+	var gen AnyGenerator
+	switch {
+	case isExpr:
+		gen = NewExprGenerator(expr, t.Kind, b.rand(), b.logger)
+	case isTemplate:
+		gen = NewTemplateGenerator(tmpl, t.Kind, b.rand(), b.logger)
+	default:
+		if gen, err = b.build(field.Type, tags); err != nil {
+			return nil, err
+		}
+	}
+	if len(relations) > 1 || !isExpr && !isTemplate {
+		gen = NewConstraintGenerator(gen, relations, b.options.Refs, b.rand(), b.logger)
+	}
+	return gen, nil
+}
+
+// buildRef builds a generator choosing values of the field of another struct given by the "ref" tag, like "User.ID".
+// The referenced field has to be of the same type.
+func (b *Builder) buildRef(t *typeinfo.Type, tags map[string]string) (AnyGenerator, error) {
+	refType, err := b.options.Refs.Resolve(tags["ref"])
+	if err != nil {
+		b.logger.Error("Failed to resolve reference", "ref", tags["ref"], "error", err)
+		return nil, err
+	}
+	if refType, _ = refType.Deref(); refType.String() != t.String() {
+		b.logger.Error("Referenced field has a different type", "ref", tags["ref"], "type", t, "refType", refType)
+		return nil, fmt.Errorf("ref %s: field of type %s can not reference %s", tags["ref"], t, refType)
+	}
+	return NewRefGenerator(b.options.Refs, tags, b.rand(), b.logger), nil
+}
+
+func (b *Builder) buildPointer(t *typeinfo.Type, tags map[string]string) (AnyGenerator, error) {
+	elemTags := tags
+	if _, ok := tags["nullable"]; ok || t.Elem.Kind == typeinfo.Pointer {
+		// only the outermost pointer of "**T" can be nil
+		elemTags = make(map[string]string, len(tags))
+		for key, value := range tags {
+			elemTags[key] = value
+		}
+		elemTags["nullable"] = "0"
+	}
+
+	elem, err := b.buildAt("*", t.Elem, elemTags)
+	if err != nil {
+		return nil, err
+	}
+	if _, cut := elem.(*NilGenerator); cut {
+		return elem, nil
+	}
+	return NewPointerGenerator(elem, tags, b.options.Nullable, b.rand(), b.logger), nil
+}
+
+func (b *Builder) buildSlice(t *typeinfo.Type, tags map[string]string) (AnyGenerator, error) {
+	if isBytes(t, tags) {
+		if t.Kind == typeinfo.Array {
+			return &GenericGenerator[Bytes]{impl: NewByteArrayGenerator(int(t.Len), tags, b.rand(), b.logger)}, nil
+		}
+		return &GenericGenerator[Bytes]{impl: NewBytesGenerator(tags, b.rand(), b.logger)}, nil
+	}
+
+	elem, err := b.buildAt("[]", t.Elem, SubTags(tags, "elem"))
+	if err != nil {
+		return nil, fmt.Errorf("element: %w", err)
+	}
+	if _, cut := elem.(*NilGenerator); cut && t.Kind == typeinfo.Slice {
+		// elements nested deeper than MaxDepth, like "Children []Node" of the last level, produce empty slices
+		return &GenericGenerator[[]any]{impl: NewArrayGenerator(elem, 0, b.rand(), b.logger)}, nil
+	}
+	if t.Kind == typeinfo.Array {
+		return &GenericGenerator[[]any]{impl: NewArrayGenerator(elem, int(t.Len), b.rand(), b.logger)}, nil
+	}
+	return &GenericGenerator[[]any]{impl: NewSliceGenerator(elem, tags, b.rand(), b.logger)}, nil
+}
+
+func (b *Builder) buildMap(t *typeinfo.Type, tags map[string]string) (AnyGenerator, error) {
+	key, err := b.buildAt("keys", t.Key, SubTags(tags, "keys"))
+	if err != nil {
+		return nil, fmt.Errorf("map key: %w", err)
+	}
+	value, err := b.buildAt("values", t.Elem, SubTags(tags, "values"))
+	if err != nil {
+		return nil, fmt.Errorf("map value: %w", err)
+	}
+	if _, cut := value.(*NilGenerator); cut {
+		// values nested deeper than MaxDepth, like "Children map[string]*Node" of the last level, produce empty maps
+		return &GenericGenerator[[]MapEntry]{impl: &MapGenerator{key, value, 0, 0, BaseGenerator{b.rand(), b.logger}}}, nil
+	}
+	return &GenericGenerator[[]MapEntry]{impl: NewMapGenerator(key, value, tags, b.rand(), b.logger)}, nil
+}
+
+// isEnum reports whether values of the type are chosen from its declared constants.
+// It is the case for named types with two or more constants, like "type Status int" with iota constants,
+// unless they have a registered generator or the tags configure the value, e.g. with "min" or "kind".
+func isEnum(t *typeinfo.Type, tags map[string]string) bool {
+	if len(t.Enum) < 2 {
+		return false
+	}
+	if _, ok := NamedGeneratorFactories[t.QualifiedName()]; ok {
+		return false
+	}
+	for key := range tags {
+		if key != "locale" && key != "nullable" {
+			return false
+		}
+	}
+	return true
+}
+
+// isBytes reports whether a slice or an array is generated as bytes, like "[]byte" or "[32]byte".
+// Elements of named types or configured with "elem." tags are generated one by one.
+func isBytes(t *typeinfo.Type, tags map[string]string) bool {
+	return t.Elem.Kind == typeinfo.Uint8 && !t.Elem.IsNamed() && len(SubTags(tags, "elem")) == 0
+}
+
+// contextual returns the tags with the current locale and the reference time,
+// unless they have their own "locale" and "now" tags.
+func (b *Builder) contextual(tags map[string]string) map[string]string {
+	context := make(map[string]string, 2)
+	if b.locale != "" {
+		context["locale"] = b.locale
+	}
+	if !b.options.Now.IsZero() {
+		context["now"] = b.options.Now.Format(time.RFC3339Nano)
+	}
+	for key := range tags {
+		delete(context, key)
+	}
+	if len(context) == 0 {
+		return tags
+	}
+
+	result := make(map[string]string, len(tags)+len(context))
+	for key, value := range tags {
+		result[key] = value
+	}
+	for key, value := range context {
+		result[key] = value
+	}
+	return result
+}
+
+// SubTags returns the tags addressed to a part of a composite type,
+// e.g. SubTags(tags, "elem") turns "elem.min=1;elem.max=10" into "min=1;max=10".
+func SubTags(tags map[string]string, prefix string) map[string]string {
+	result := make(map[string]string)
+	prefix += "."
+	for key, value := range tags {
+		if name, ok := strings.CutPrefix(key, prefix); ok {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// NilGenerator always returns nil.
+// It is used in place of structs nested deeper than MaxDepth,
+// for example the last level of a recursive type definition.
+type NilGenerator struct{}
+
+// EvaluateAny returns nil
+func (g *NilGenerator) EvaluateAny() (any, error) {
+	return nil, nil
+}
@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func testBuilder(maxDepth int) *Builder {
+	return NewBuilder(Options{MaxDepth: maxDepth}, NewRandSource(1), testutils.TestLogger())
+}
+
+func TestBuilder_NestedStruct(t *testing.T) {
+	address := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "example.com/models", Name: "Address", Fields: []typeinfo.Field{
+		{Name: "City", Type: &typeinfo.Type{Kind: typeinfo.String}, MockTags: map[string]string{"prefix": "city_"}},
+	}}
+	user := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "example.com/models", Name: "User", Fields: []typeinfo.Field{
+		{Name: "ID", Type: &typeinfo.Type{Kind: typeinfo.Int}},
+		{Name: "Address", Type: address},
+	}}
+
+	g, err := testBuilder(5).Build(user, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	val, err := g.EvaluateAny()
+	if err != nil {
+		t.Fatalf("EvaluateAny() error = %v", err)
+	}
+
+	record := val.(*Record)
+	if len(record.Fields) != 2 || record.Fields[1].Field.Name != "Address" {
+		t.Fatalf("Unexpected record fields: %+v", record.Fields)
+	}
+	nested, ok := record.Fields[1].Value.(*Record)
+	if !ok {
+		t.Fatalf("Expected nested record, got %T", record.Fields[1].Value)
+	}
+	if city := nested.Fields[0].Value.(string); len(city) != len("city_")+defaultLength {
+		t.Errorf("Unexpected nested value: %s", city)
+	}
+}
+
+func TestBuilder_RecursionLimits(t *testing.T) {
+	node := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "example.com/models", Name: "Node"}
+	node.Fields = []typeinfo.Field{
+		{Name: "Value", Type: &typeinfo.Type{Kind: typeinfo.Int}},
+		{Name: "Parent", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: node}},
+	}
+	leaf := &typeinfo.Type{Kind: typeinfo.Struct, Fields: []typeinfo.Field{
+		{Name: "Value", Type: &typeinfo.Type{Kind: typeinfo.Int}},
+	}}
+	tree := &typeinfo.Type{Kind: typeinfo.Struct, Fields: []typeinfo.Field{
+		{Name: "Leaf", Type: leaf},
+	}}
+
+	tests := []struct {
+		name     string
+		typ      *typeinfo.Type
+		maxDepth int
+		wantNil  bool
+	}{
+		{name: "self-reference within depth", typ: node, maxDepth: 2, wantNil: false},
+		{name: "self-reference beyond depth", typ: node, maxDepth: 0, wantNil: true},
+		{name: "nested within depth", typ: tree, maxDepth: 1, wantNil: false},
+		{name: "nested beyond depth", typ: tree, maxDepth: 0, wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := testBuilder(tt.maxDepth).Build(tt.typ, nil)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			val, _ := g.EvaluateAny()
+			nested := val.(*Record).Fields[len(tt.typ.Fields)-1].Value
+			if (nested == nil) != tt.wantNil {
+				t.Errorf("Got nested value %v, want nil: %v", nested, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestBuilder_RecursiveDepth(t *testing.T) {
+	node := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "example.com/models", Name: "Node"}
+	node.Fields = []typeinfo.Field{
+		{Name: "Parent", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: node}},
+		{Name: "Children", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: node}, MockTags: map[string]string{"minlen": "1", "maxlen": "1"}},
+	}
+
+	for _, maxDepth := range []int{0, 1, 3} {
+		g, err := testBuilder(maxDepth).Build(node, nil)
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		val, _ := g.EvaluateAny()
+
+		parents, children := 0, 0
+		for record := val.(*Record); record.Fields[0].Value != nil; record = record.Fields[0].Value.(*Record) {
+			parents++
+		}
+		for record := val.(*Record); len(record.Fields[1].Value.([]any)) > 0; record = record.Fields[1].Value.([]any)[0].(*Record) {
+			children++
+		}
+		if parents != maxDepth || children != maxDepth {
+			t.Errorf("maxDepth %d: got %d parents and %d levels of children", maxDepth, parents, children)
+		}
+	}
+}
+
+func TestBuilder_UnknownType(t *testing.T) {
+	_, err := testBuilder(5).Build(&typeinfo.Type{Kind: typeinfo.Invalid}, nil)
+	if !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Expected ErrUnknownType, got %v", err)
+	}
+}
+
+func TestBuilder_InvalidTags(t *testing.T) {
+	typ := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{
+		{Name: "ID", Type: &typeinfo.Type{Kind: typeinfo.Int}},
+		{Name: "Name", Type: &typeinfo.Type{Kind: typeinfo.String}, MockTags: map[string]string{"kind": "full_name"}},
+	}}
+	_, err := testBuilder(5).Build(typ, nil)
+	if !errors.Is(err, ErrInvalidTags) || !strings.Contains(err.Error(), "field Name") {
+		t.Errorf("Expected ErrInvalidTags of field Name, got %v", err)
+	}
+}
+
+func TestBuilder_UnsupportedFields(t *testing.T) {
+	invalid := &typeinfo.Type{Kind: typeinfo.Invalid}
+	typ := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Job", Fields: []typeinfo.Field{
+		{Name: "ID", Type: &typeinfo.Type{Kind: typeinfo.Int}},
+		{Name: "Done", Type: invalid},
+		{Name: "Hooks", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: invalid}},
+	}}
+	g, err := testBuilder(5).Build(typ, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	val, err := g.EvaluateAny()
+	if err != nil {
+		t.Fatalf("EvaluateAny() error = %v", err)
+	}
+	record := val.(*Record)
+	if record.Fields[0].Value == nil || record.Fields[1].Value != nil || record.Fields[2].Value != nil {
+		t.Errorf("Unsupported fields are not left empty: %+v", record.Fields)
+	}
+}
+
+func TestBuilder_Locale(t *testing.T) {
+	city := &typeinfo.Type{Kind: typeinfo.String}
+	address := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Address", Fields: []typeinfo.Field{
+		{Name: "City", Type: city, MockTags: map[string]string{"kind": "city"}},
+		{Name: "Home", Type: city, MockTags: map[string]string{"kind": "city", "locale": "ja_JP"}},
+	}}
+	user := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{
+		{Name: "City", Type: city, MockTags: map[string]string{"kind": "city"}},
+		{Name: "Address", Type: address, MockTags: map[string]string{"locale": "ru_RU"}},
+	}}
+
+	builder := NewBuilder(Options{MaxDepth: 5, Locale: "de_DE"}, NewRandSource(1), testutils.TestLogger())
+	g, err := builder.Build(user, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	val, _ := g.EvaluateAny()
+	record := val.(*Record)
+	nested := record.Fields[1].Value.(*Record)
+
+	checks := []struct {
+		value  any
+		locale string
+	}{
+		{record.Fields[0].Value, "de_DE"},
+		{nested.Fields[0].Value, "ru_RU"},
+		{nested.Fields[1].Value, "ja_JP"},
+	}
+	for _, check := range checks {
+		if !slices.Contains(Locales[check.locale].Cities, check.value.(string)) {
+			t.Errorf("City %q is not from the %s locale", check.value, check.locale)
+		}
+	}
+}
+
+func TestBuilder_Now(t *testing.T) {
+	timeType := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "time", Name: "Time"}
+	event := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Event", Fields: []typeinfo.Field{
+		{Name: "At", Type: timeType},
+		{Name: "Day", Type: &typeinfo.Type{Kind: typeinfo.String}, MockTags: map[string]string{"layout": "DateOnly"}},
+		{Name: "Own", Type: timeType, MockTags: map[string]string{"now": "2000-01-01T00:00:00Z"}},
+	}}
+
+	now := time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC)
+	builder := NewBuilder(Options{MaxDepth: 5, Now: now}, NewRandSource(1), testutils.TestLogger())
+	g, err := builder.Build(event, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	val, _ := g.EvaluateAny()
+	record := val.(*Record)
+
+	if at := record.Fields[0].Value.(time.Time); !at.Equal(now) {
+		t.Errorf("At = %s, want the reference time %s", at, now)
+	}
+	if day := record.Fields[1].Value.(string); day != "2025-06-15" {
+		t.Errorf("Day = %s, want 2025-06-15", day)
+	}
+	if own := record.Fields[2].Value.(time.Time); own.Year() != 2000 {
+		t.Errorf("Own = %s, want the time of its now tag", own)
+	}
+}
+
+func TestBuilder_Enum(t *testing.T) {
+	status := &typeinfo.Type{Kind: typeinfo.Int, PkgPath: "example.com/models", Name: "Status", Enum: []string{"1", "2", "3"}}
+	account := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Account", Fields: []typeinfo.Field{
+		{Name: "Status", Type: status},
+		{Name: "Previous", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: status}, MockTags: map[string]string{"nullable": "0"}},
+		{Name: "Custom", Type: status, MockTags: map[string]string{"oneof": "7"}},
+		{Name: "Ranged", Type: status, MockTags: map[string]string{"min": "10", "max": "20"}},
+	}}
+
+	g, err := testBuilder(5).Build(account, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for range 20 {
+		val, _ := g.EvaluateAny()
+		fields := val.(*Record).Fields
+		if v := fields[0].Value.(int); v < 1 || v > 3 {
+			t.Errorf("Status %d is not a declared constant", v)
+		}
+		if v := fields[1].Value.(int); v < 1 || v > 3 {
+			t.Errorf("Previous %d is not a declared constant", v)
+		}
+		if v := fields[2].Value.(int); v != 7 {
+			t.Errorf("Custom = %d, want oneof value 7", v)
+		}
+		if v := fields[3].Value.(int); v < 10 || v > 20 {
+			t.Errorf("Ranged = %d, want tags to take precedence over constants", v)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/rand"
+)
+
+// ByteEncodings are the supported values of the "encoding" tag of byte slices and arrays.
+var ByteEncodings = map[string]func([]byte) string{
+	"base64": base64.StdEncoding.EncodeToString,
+	"hex":    hex.EncodeToString,
+}
+
+const defaultByteEncoding = "base64"
+
+// Bytes is a generated byte slice or array.
+// Text formats write it as a string in its encoding, base64 by default like in encoding/json.
+type Bytes struct {
+	Data     []byte
+	Encoding string // one of ByteEncodings
+}
+
+// String returns the encoded bytes.
+func (b Bytes) String() string {
+	return ByteEncodings[b.Encoding](b.Data)
+}
+
+// MarshalText returns the encoded bytes, so that encoders write them as a string.
+func (b Bytes) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// BytesGenerator generates random byte slices and arrays.
+type BytesGenerator struct {
+	minLen   int
+	maxLen   int
+	encoding string
+	BaseGenerator
+}
+
+// NewBytesGenerator creates a new BytesGenerator using "minlen", "maxlen" and "encoding" tags.
+// Lengths default to 1 and 5 bytes like for other slices, the encoding defaults to base64.
+// It panics on unknown encodings.
+func NewBytesGenerator(tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[Bytes] {
+	minLen, maxLen := parseLenRange(tags, logger)
+	return newBytesGenerator(minLen, maxLen, tags, rand, logger)
+}
+
+// NewByteArrayGenerator creates a BytesGenerator that always generates exactly length bytes.
+func NewByteArrayGenerator(length int, tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[Bytes] {
+	return newBytesGenerator(length, length, tags, rand, logger)
+}
+
+func newBytesGenerator(minLen, maxLen int, tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[Bytes] {
+	encoding, ok := tags["encoding"]
+	if !ok {
+		encoding = defaultByteEncoding
+	}
+	if _, ok := ByteEncodings[encoding]; !ok {
+		logger.Error("Unknown byte encoding provided", "encoding", encoding)
+		panic(fmt.Sprintf("unknown byte encoding provided: %q", encoding))
+	}
+
+	logger.Debug("BytesGenerator created", "minlen", minLen, "maxlen", maxLen, "encoding", encoding)
+	return &BytesGenerator{minLen, maxLen, encoding, BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns random bytes of a length within the configured range.
+func (g *BytesGenerator) Evaluate() (Bytes, error) {
+	result := make([]byte, g.minLen+g.rand.Intn(g.maxLen-g.minLen+1))
+	g.rand.Read(result)
+	g.logger.Debug("Evaluate generated bytes", "len", len(result))
+	return Bytes{result, g.encoding}, nil
+}
+
+func (g *BytesGenerator) Validate() error {
+	return nil
+}
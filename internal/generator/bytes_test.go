@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+)
+
+func TestBytesGenerator_Encodings(t *testing.T) {
+	g := NewBytesGenerator(map[string]string{"minlen": "16", "maxlen": "16"}, testRand(), testutils.TestLogger())
+	val, _ := g.Evaluate()
+	if len(val.Data) != 16 {
+		t.Errorf("Unexpected length: %d", len(val.Data))
+	}
+	if val.String() != base64.StdEncoding.EncodeToString(val.Data) {
+		t.Errorf("Expected base64 by default, got %s", val)
+	}
+
+	g = NewByteArrayGenerator(4, map[string]string{"encoding": "hex"}, testRand(), testutils.TestLogger())
+	val, _ = g.Evaluate()
+	text, _ := val.MarshalText()
+	if len(val.Data) != 4 || string(text) != hex.EncodeToString(val.Data) {
+		t.Errorf("Unexpected hex bytes: %x encoded as %s", val.Data, text)
+	}
+}
+
+func TestBytesGenerator_UnknownEncoding(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for unknown encoding")
+		}
+	}()
+	NewBytesGenerator(map[string]string{"encoding": "base32"}, testRand(), testutils.TestLogger())
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"log/slog"
+	"math/rand"
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ComplexGenerator generates complex numbers
+// with real and imaginary parts based on min and max values.
+type ComplexGenerator[T constraints.Complex] struct {
+	part func() float64 // generates a real or an imaginary part
+	BaseGenerator
+}
+
+// NewComplexGenerator creates a new ComplexGenerator using "min" and "max" from tags,
+// that limit both the real and the imaginary part.
+// Defaults to min and max values of the part type (float32 or float64) if not provided.
+func NewComplexGenerator[T constraints.Complex](tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[T] {
+	var zero T
+	var part func() float64
+
+	switch reflect.TypeOf(zero).Kind() {
+	case reflect.Complex64:
+		g := NewFloatGenerator[float32](tags, rand, logger)
+		part = func() float64 {
+			value, _ := g.Evaluate()
+			return float64(value)
+		}
+	default:
+		g := NewFloatGenerator[float64](tags, rand, logger)
+		part = func() float64 {
+			value, _ := g.Evaluate()
+			return value
+		}
+	}
+
+	logger.Debug("ComplexGenerator created", "type", reflect.TypeOf(zero).String())
+	return &ComplexGenerator[T]{part, BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns a random complex number with both parts between min and max values.
+func (g *ComplexGenerator[T]) Evaluate() (T, error) {
+	value := complex(g.part(), g.part())
+	g.logger.Debug("Evaluate generated value", "value", value)
+	return T(value), nil
+}
+
+func (g *ComplexGenerator[T]) Validate() error {
+	return nil
+}
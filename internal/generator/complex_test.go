@@ -0,0 +1,22 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+)
+
+func TestComplexGenerator_Range(t *testing.T) {
+	tags := map[string]string{"min": "-1", "max": "1"}
+	g64 := NewComplexGenerator[complex64](tags, testRand(), testutils.TestLogger())
+	g128 := NewComplexGenerator[complex128](tags, testRand(), testutils.TestLogger())
+	for range 100 {
+		v64, _ := g64.Evaluate()
+		v128, _ := g128.Evaluate()
+		for _, part := range []float64{float64(real(v64)), float64(imag(v64)), real(v128), imag(v128)} {
+			if part < -1 || part > 1 {
+				t.Fatalf("Part out of range: %v", part)
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
 	"golang.org/x/exp/constraints"
 )
 
@@ -13,27 +14,89 @@ type GeneratorFactory interface {
 	Create(tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator
 }
 
-var GeneratorFactories = map[string]GeneratorFactory{
-	"string":       StringFactory{},
-	"&{time Time}": TimeFactory{},
-	"&{uuid UUID}": UUIDFactory{},
-	"int":          SignedFactory[int]{},
-	"int8":         SignedFactory[int8]{},
-	"int16":        SignedFactory[int16]{},
-	"int32":        SignedFactory[int32]{},
-	"int64":        SignedFactory[int64]{},
-	"uint":         UnsignedFactory[uint]{},
-	"uint8":        UnsignedFactory[uint8]{},
-	"uint16":       UnsignedFactory[uint16]{},
-	"uint32":       UnsignedFactory[uint32]{},
-	"uint64":       UnsignedFactory[uint64]{},
-	"float32":      FloatFactory[float32]{},
-	"float64":      FloatFactory[float64]{},
+// GeneratorFactories maps kinds of predeclared types to their generator factories.
+// Named types with a predeclared underlying type (e.g. "type Status string")
+// are generated by the factory of their kind.
+var GeneratorFactories = map[typeinfo.Kind]GeneratorFactory{
+	typeinfo.Bool:       BoolFactory{},
+	typeinfo.String:     StringFactory{},
+	typeinfo.Int:        SignedFactory[int]{},
+	typeinfo.Int8:       SignedFactory[int8]{},
+	typeinfo.Int16:      SignedFactory[int16]{},
+	typeinfo.Int32:      SignedFactory[int32]{},
+	typeinfo.Int64:      SignedFactory[int64]{},
+	typeinfo.Uint:       UnsignedFactory[uint]{},
+	typeinfo.Uint8:      UnsignedFactory[uint8]{},
+	typeinfo.Uint16:     UnsignedFactory[uint16]{},
+	typeinfo.Uint32:     UnsignedFactory[uint32]{},
+	typeinfo.Uint64:     UnsignedFactory[uint64]{},
+	typeinfo.Uintptr:    UnsignedFactory[uintptr]{},
+	typeinfo.Float32:    FloatFactory[float32]{},
+	typeinfo.Float64:    FloatFactory[float64]{},
+	typeinfo.Complex64:  ComplexFactory[complex64]{},
+	typeinfo.Complex128: ComplexFactory[complex128]{},
+}
+
+// NamedGeneratorFactories maps package path qualified names of named types
+// to their generator factories. They take precedence over GeneratorFactories.
+var NamedGeneratorFactories = map[string]GeneratorFactory{
+	"time.Time":                   TimeFactory{},
+	"time.Duration":               DurationFactory{},
+	"github.com/google/uuid.UUID": UUIDFactory{},
+}
+
+// LookupFactory returns a factory able to generate values of the given type.
+func LookupFactory(t *typeinfo.Type) (GeneratorFactory, bool) {
+	if t == nil {
+		return nil, false
+	}
+	if t.IsNamed() {
+		if factory, ok := NamedGeneratorFactories[t.QualifiedName()]; ok {
+			return factory, true
+		}
+	}
+	if !t.Kind.IsBasic() {
+		return nil, false
+	}
+	if t.Kind == typeinfo.Int32 && t.Name == "rune" {
+		// the rune alias of int32 is generated as characters
+		return RuneFactory{}, true
+	}
+	factory, ok := GeneratorFactories[t.Kind]
+	return factory, ok
+}
+
+type BoolFactory struct{}
+
+func (f BoolFactory) Create(tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	return &GenericGenerator[bool]{impl: NewBoolGenerator(tags, rand, logger)}
+}
+
+type RuneFactory struct{}
+
+func (f RuneFactory) Create(tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	return &GenericGenerator[int32]{impl: NewRuneGenerator(tags, rand, logger)}
 }
 
 type StringFactory struct{}
 
 func (f StringFactory) Create(tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	_, kind := tags["kind"]
+	_, pattern := tags["pattern"]
+	_, layout := tags["layout"]
+	if kind && pattern || kind && layout || pattern && layout {
+		logger.Error("More than one of kind, pattern and layout tags provided", "kind", tags["kind"], "pattern", tags["pattern"], "layout", tags["layout"])
+		panic("kind, pattern and layout tags can not be combined")
+	}
+	if layout {
+		return &GenericGenerator[string]{impl: NewTimeStringGenerator(tags, rand, logger)}
+	}
+	if kind {
+		return &GenericGenerator[string]{impl: NewKindGenerator(tags, rand, logger)}
+	}
+	if pattern {
+		return &GenericGenerator[string]{impl: NewPatternGenerator(tags, rand, logger)}
+	}
 	return &GenericGenerator[string]{impl: NewStringGenerator(tags, rand, logger)}
 }
 
@@ -43,6 +106,12 @@ func (f TimeFactory) Create(tags map[string]string, rand *rand.Rand, logger *slo
 	return &GenericGenerator[time.Time]{impl: NewTimeGenerator(tags, rand, logger)}
 }
 
+type DurationFactory struct{}
+
+func (f DurationFactory) Create(tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	return &GenericGenerator[time.Duration]{impl: NewDurationGenerator(tags, rand, logger)}
+}
+
 type UUIDFactory struct{}
 
 func (f UUIDFactory) Create(tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
@@ -66,3 +135,9 @@ type FloatFactory[T constraints.Float] struct{}
 func (f FloatFactory[T]) Create(tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
 	return &GenericGenerator[T]{impl: NewFloatGenerator[T](tags, rand, logger)}
 }
+
+type ComplexFactory[T constraints.Complex] struct{}
+
+func (f ComplexFactory[T]) Create(tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	return &GenericGenerator[T]{impl: NewComplexGenerator[T](tags, rand, logger)}
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestLookupFactory(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  *typeinfo.Type
+		want GeneratorFactory
+	}{
+		{
+			name: "predeclared",
+			typ:  &typeinfo.Type{Kind: typeinfo.Int16},
+			want: SignedFactory[int16]{},
+		},
+		{
+			name: "rune alias",
+			typ:  &typeinfo.Type{Kind: typeinfo.Int32, Name: "rune"},
+			want: RuneFactory{},
+		},
+		{
+			name: "complex",
+			typ:  &typeinfo.Type{Kind: typeinfo.Complex64},
+			want: ComplexFactory[complex64]{},
+		},
+		{
+			name: "named with basic underlying type",
+			typ:  &typeinfo.Type{Kind: typeinfo.String, PkgPath: "example.com/models", Name: "Status"},
+			want: StringFactory{},
+		},
+		{
+			name: "registered named type",
+			typ:  &typeinfo.Type{Kind: typeinfo.Array, PkgPath: "github.com/google/uuid", Name: "UUID", Len: 16},
+			want: UUIDFactory{},
+		},
+		{
+			name: "duration",
+			typ:  &typeinfo.Type{Kind: typeinfo.Int64, PkgPath: "time", Name: "Duration", Enum: []string{"1", "1000"}},
+			want: DurationFactory{},
+		},
+		{
+			name: "unregistered struct",
+			typ:  &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "example.com/models", Name: "Address"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LookupFactory(tt.typ)
+			if tt.want == nil {
+				if ok {
+					t.Errorf("LookupFactory(%s) = %T, want no factory", tt.typ, got)
+				}
+				return
+			}
+			if !ok || got != tt.want {
+				t.Errorf("LookupFactory(%s) = %T, want %T", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"go/constant"
 	"log/slog"
 	"math"
 	"math/rand"
@@ -82,6 +83,19 @@ func (g *FloatGenerator[T]) Evaluate() (T, error) {
 	return T(value), nil
 }
 
+// evaluateWithin returns a random floating number within the range and the bounds.
+func (g *FloatGenerator[T]) evaluateWithin(low, high any) (any, bool, error) {
+	min, max, ok := narrowRange(constant.MakeFloat64(g.min), constant.MakeFloat64(g.max), low, high)
+	if !ok {
+		return nil, false, nil
+	}
+	narrowed := &FloatGenerator[T]{BaseGenerator: g.BaseGenerator}
+	narrowed.min, _ = constant.Float64Val(min)
+	narrowed.max, _ = constant.Float64Val(max)
+	value, err := narrowed.Evaluate()
+	return value, true, err
+}
+
 func floatClamp(val, min, max float64) float64 {
 	if val < min {
 		return min
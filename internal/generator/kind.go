@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"math/rand"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// StringKind generates a realistic string of some kind, like an email or a city.
+// Names, addresses, phones, postcodes, dates and companies follow the given locale.
+// count is the value of the "len" tag, used as the number of words, sentences or paragraphs
+// by lorem kinds. It is 0 if the tag is not provided.
+type StringKind func(rand *rand.Rand, locale *Locale, count int) string
+
+// StringKinds maps values of the "kind" tag to their generators.
+var StringKinds = map[string]StringKind{
+	"email":        email,
+	"first_name":   func(r *rand.Rand, l *Locale, _ int) string { return l.firstName(r) },
+	"last_name":    func(r *rand.Rand, l *Locale, _ int) string { return l.lastName(r) },
+	"name":         func(r *rand.Rand, l *Locale, _ int) string { return l.name(r) },
+	"username":     username,
+	"phone":        func(r *rand.Rand, l *Locale, _ int) string { return expand(r, l.PhoneFormat, nil) },
+	"url":          url,
+	"domain":       func(r *rand.Rand, _ *Locale, _ int) string { return domain(r) },
+	"ipv4":         ipv4,
+	"ipv6":         ipv6,
+	"mac":          mac,
+	"address":      func(r *rand.Rand, l *Locale, _ int) string { return l.address(r) },
+	"city":         func(r *rand.Rand, l *Locale, _ int) string { return pick(r, l.Cities) },
+	"country_code": func(r *rand.Rand, _ *Locale, _ int) string { return pick(r, countryCodes) },
+	"postcode":     func(r *rand.Rand, l *Locale, _ int) string { return expand(r, l.PostcodeFormat, nil) },
+	"date":         func(r *rand.Rand, l *Locale, _ int) string { return l.date(r) },
+	"company":      func(r *rand.Rand, l *Locale, _ int) string { return l.company(r) },
+	"job_title":    jobTitle,
+	"words":        words,
+	"sentences":    sentences,
+	"paragraphs":   paragraphs,
+	"hex_color":    func(r *rand.Rand, _ *Locale, _ int) string { return fmt.Sprintf("#%06x", r.Intn(1<<24)) },
+	"credit_card":  creditCard,
+	"iban":         iban,
+	"slug":         slug,
+}
+
+// KindGenerator generates strings of a kind given by the "kind" tag.
+type KindGenerator struct {
+	kind   StringKind
+	locale *Locale
+	count  int
+	prefix string
+	suffix string
+	BaseGenerator
+}
+
+// NewKindGenerator creates a new KindGenerator using "kind", "locale", "len", "prefix" and "suffix" tags.
+// The locale defaults to DefaultLocale. It panics on unknown kinds and locales.
+func NewKindGenerator(tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[string] {
+	kind, ok := StringKinds[tags["kind"]]
+	if !ok {
+		logger.Error("Unknown string kind provided", "kind", tags["kind"])
+		panic(fmt.Sprintf("unknown string kind provided: %q", tags["kind"]))
+	}
+
+	localeName := tags["locale"]
+	if localeName == "" {
+		localeName = DefaultLocale
+	}
+	locale, ok := Locales[localeName]
+	if !ok {
+		err := checkLocale(localeName)
+		logger.Error("Unknown locale provided", "locale", localeName, "error", err)
+		panic(err)
+	}
+
+	var count int
+	if lenVal, ok := tags["len"]; ok {
+		c, err := strconv.Atoi(lenVal)
+		if err != nil {
+			logger.Error("Failed to parse len tag", "len", lenVal, "error", err)
+			panic(err)
+		}
+		if c < 1 {
+			logger.Error("Invalid length provided", "length", c)
+			panic(fmt.Sprintf("invalid length provided: %d", c))
+		}
+		count = c
+	}
+
+	logger.Debug("KindGenerator created", "kind", tags["kind"], "locale", localeName, "count", count, "prefix", tags["prefix"], "suffix", tags["suffix"])
+	return &KindGenerator{kind, locale, count, tags["prefix"], tags["suffix"], BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns a generated string of the kind including prefix and suffix.
+func (g *KindGenerator) Evaluate() (string, error) {
+	result := g.prefix + g.kind(g.rand, g.locale, g.count) + g.suffix
+	g.logger.Debug("Evaluate generated value", "value", result)
+	return result, nil
+}
+
+func pick(rand *rand.Rand, values []string) string {
+	return values[rand.Intn(len(values))]
+}
+
+// digits returns n random decimal digits.
+func digits(rand *rand.Rand, n int) string {
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = byte('0' + rand.Intn(10))
+	}
+	return string(result)
+}
+
+// asciiNames returns the locale with ASCII names, used by emails, usernames and domains
+// which are not localized.
+func asciiNames() *Locale {
+	return Locales[DefaultLocale]
+}
+
+func username(rand *rand.Rand, _ *Locale, _ int) string {
+	names := asciiNames()
+	first, last := strings.ToLower(names.firstName(rand)), strings.ToLower(names.lastName(rand))
+	switch rand.Intn(3) {
+	case 0:
+		return first + "." + last
+	case 1:
+		return first[:1] + last + strconv.Itoa(rand.Intn(100))
+	}
+	return first + "_" + last + strconv.Itoa(1950+rand.Intn(60))
+}
+
+func email(rand *rand.Rand, _ *Locale, _ int) string {
+	names := asciiNames()
+	local := strings.ToLower(names.firstName(rand) + "." + names.lastName(rand))
+	if rand.Intn(2) == 0 {
+		local += strconv.Itoa(rand.Intn(1000))
+	}
+	return local + "@" + pick(rand, emailDomains)
+}
+
+func domain(rand *rand.Rand) string {
+	return strings.ToLower(asciiNames().lastName(rand)) + "-" + pick(rand, loremWords) + "." + pick(rand, domainSuffixes)
+}
+
+func url(rand *rand.Rand, _ *Locale, _ int) string {
+	result := "https://"
+	if rand.Intn(2) == 0 {
+		result += "www."
+	}
+	result += domain(rand)
+	if rand.Intn(2) == 0 {
+		result += "/" + slug(rand, nil, 0)
+	}
+	return result
+}
+
+// ipv4 returns a public looking unicast address, with the first octet from 1 to 223.
+func ipv4(rand *rand.Rand, _ *Locale, _ int) string {
+	return fmt.Sprintf("%d.%d.%d.%d", 1+rand.Intn(223), rand.Intn(256), rand.Intn(256), rand.Intn(256))
+}
+
+func ipv6(rand *rand.Rand, _ *Locale, _ int) string {
+	var addr [16]byte
+	rand.Read(addr[:])
+	addr[0] = 0x20 | addr[0]&0x1f // global unicast, 2000::/3
+	return netip.AddrFrom16(addr).String()
+}
+
+// mac returns a locally administered unicast MAC address.
+func mac(rand *rand.Rand, _ *Locale, _ int) string {
+	addr := make(net.HardwareAddr, 6)
+	rand.Read(addr)
+	addr[0] = addr[0]&^0x01 | 0x02
+	return addr.String()
+}
+
+func jobTitle(rand *rand.Rand, _ *Locale, _ int) string {
+	return pick(rand, jobLevels) + " " + pick(rand, jobAreas) + " " + pick(rand, jobRoles)
+}
+
+// words returns count lorem words, 3 by default.
+func words(rand *rand.Rand, _ *Locale, count int) string {
+	if count == 0 {
+		count = 3
+	}
+	result := make([]string, count)
+	for i := range result {
+		result[i] = pick(rand, loremWords)
+	}
+	return strings.Join(result, " ")
+}
+
+func sentence(rand *rand.Rand) string {
+	result := []rune(words(rand, nil, 4+rand.Intn(9)))
+	result[0] = unicode.ToUpper(result[0])
+	return string(result) + "."
+}
+
+// sentences returns count lorem sentences, 1 by default.
+func sentences(rand *rand.Rand, _ *Locale, count int) string {
+	result := make([]string, max(count, 1))
+	for i := range result {
+		result[i] = sentence(rand)
+	}
+	return strings.Join(result, " ")
+}
+
+// paragraphs returns count lorem paragraphs separated by empty lines, 1 by default.
+func paragraphs(rand *rand.Rand, _ *Locale, count int) string {
+	result := make([]string, max(count, 1))
+	for i := range result {
+		result[i] = sentences(rand, nil, 3+rand.Intn(4))
+	}
+	return strings.Join(result, "\n\n")
+}
+
+func slug(rand *rand.Rand, _ *Locale, count int) string {
+	if count == 0 {
+		count = 2 + rand.Intn(3)
+	}
+	return strings.ReplaceAll(words(rand, nil, count), " ", "-")
+}
+
+// creditCard returns a Visa, Mastercard or American Express like number with a valid Luhn check digit.
+func creditCard(rand *rand.Rand, _ *Locale, _ int) string {
+	var number string
+	switch rand.Intn(3) {
+	case 0:
+		number = "4" + digits(rand, 14)
+	case 1:
+		number = strconv.Itoa(51+rand.Intn(5)) + digits(rand, 13)
+	default:
+		number = "3" + strconv.Itoa(4+3*rand.Intn(2)) + digits(rand, 12)
+	}
+	return number + strconv.Itoa(luhnDigit(number))
+}
+
+// luhnDigit returns the check digit to append to number to make it pass the Luhn check.
+func luhnDigit(number string) int {
+	sum := 0
+	for i := range number {
+		d := int(number[len(number)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// iban returns a German IBAN with valid check digits.
+func iban(rand *rand.Rand, _ *Locale, _ int) string {
+	bban := digits(rand, 18)
+	return "DE" + ibanCheckDigits("DE", bban) + bban
+}
+
+// ibanCheckDigits computes the ISO 13616 check digits of an IBAN.
+func ibanCheckDigits(country, bban string) string {
+	var numeric strings.Builder
+	for _, c := range bban + country + "00" {
+		if c >= 'A' && c <= 'Z' {
+			numeric.WriteString(strconv.Itoa(int(c-'A') + 10))
+		} else {
+			numeric.WriteRune(c)
+		}
+	}
+	n, _ := new(big.Int).SetString(numeric.String(), 10)
+	check := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+	return fmt.Sprintf("%02d", check)
+}
+
+func (g *KindGenerator) Validate() error {
+	return nil
+}
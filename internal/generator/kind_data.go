@@ -0,0 +1,37 @@
+package generator
+
+// Locale independent word lists used by semantic string kinds.
+// Locale specific data, like names and addresses, is in the locales directory.
+
+// countryCodes are ISO 3166-1 alpha-2 codes.
+var countryCodes = []string{
+	"AR", "AT", "AU", "BE", "BR", "CA", "CH", "CL", "CN", "CZ",
+	"DE", "DK", "EG", "ES", "FI", "FR", "GB", "GR", "HU", "IE",
+	"IL", "IN", "IT", "JP", "KR", "MX", "NG", "NL", "NO", "NZ",
+	"PL", "PT", "RO", "RU", "SE", "SG", "TR", "UA", "US", "ZA",
+}
+
+var jobLevels = []string{"Junior", "Senior", "Lead", "Principal", "Chief", "Associate", "Head of", "Staff"}
+
+var jobAreas = []string{
+	"Software", "Marketing", "Sales", "Finance", "Operations", "Product", "Data", "Security",
+	"Support", "Design", "Research", "Infrastructure", "Quality", "Legal", "Human Resources",
+}
+
+var jobRoles = []string{
+	"Engineer", "Manager", "Analyst", "Consultant", "Specialist", "Architect", "Designer",
+	"Coordinator", "Administrator", "Developer", "Officer", "Director", "Strategist",
+}
+
+var domainSuffixes = []string{"com", "net", "org", "io", "dev", "info", "biz", "co"}
+
+var emailDomains = []string{"example.com", "example.org", "example.net", "mail.test", "inbox.test"}
+
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+	"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+	"ad", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip",
+	"ex", "ea", "commodo", "consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate",
+	"velit", "esse", "cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint", "occaecat", "cupidatat",
+	"non", "proident", "sunt", "culpa", "qui", "officia", "deserunt", "mollit", "anim", "id", "est", "laborum",
+}
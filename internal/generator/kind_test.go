@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"net"
+	"net/mail"
+	"net/netip"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+)
+
+func TestKindGenerator_Kinds(t *testing.T) {
+	tests := []struct {
+		kind  string
+		valid func(string) bool
+	}{
+		{"email", func(s string) bool { _, err := mail.ParseAddress(s); return err == nil }},
+		{"url", func(s string) bool {
+			u, err := neturl.Parse(s)
+			return err == nil && u.Scheme == "https" && u.Host != ""
+		}},
+		{"ipv4", func(s string) bool { addr, err := netip.ParseAddr(s); return err == nil && addr.Is4() }},
+		{"ipv6", func(s string) bool { addr, err := netip.ParseAddr(s); return err == nil && addr.Is6() }},
+		{"mac", func(s string) bool { _, err := net.ParseMAC(s); return err == nil }},
+		{"name", regexp.MustCompile(`^[A-Z][a-z]+ [A-Z][a-z]+$`).MatchString},
+		{"phone", regexp.MustCompile(`^\+1 \([2-9]\d\d\) [2-9]\d\d-\d{4}$`).MatchString},
+		{"postcode", regexp.MustCompile(`^\d{5}$`).MatchString},
+		{"country_code", regexp.MustCompile(`^[A-Z]{2}$`).MatchString},
+		{"hex_color", regexp.MustCompile(`^#[0-9a-f]{6}$`).MatchString},
+		{"slug", regexp.MustCompile(`^[a-z]+(-[a-z]+)+$`).MatchString},
+		{"credit_card", luhnValid},
+		{"iban", ibanValid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			g := NewKindGenerator(map[string]string{"kind": tt.kind}, testRand(), testutils.TestLogger())
+			for range 50 {
+				val, _ := g.Evaluate()
+				if !tt.valid(val) {
+					t.Fatalf("Invalid %s generated: %q", tt.kind, val)
+				}
+			}
+		})
+	}
+}
+
+func TestKindGenerator_AllKinds(t *testing.T) {
+	for kind := range StringKinds {
+		g := NewKindGenerator(map[string]string{"kind": kind, "prefix": "<", "suffix": ">"}, testRand(), testutils.TestLogger())
+		val, _ := g.Evaluate()
+		if len(val) <= 2 || !strings.HasPrefix(val, "<") || !strings.HasSuffix(val, ">") {
+			t.Errorf("Unexpected %s value: %q", kind, val)
+		}
+	}
+}
+
+func TestKindGenerator_Count(t *testing.T) {
+	g := NewKindGenerator(map[string]string{"kind": "words", "len": "5"}, testRand(), testutils.TestLogger())
+	val, _ := g.Evaluate()
+	if n := len(strings.Fields(val)); n != 5 {
+		t.Errorf("Expected 5 words, got %d: %q", n, val)
+	}
+
+	g = NewKindGenerator(map[string]string{"kind": "paragraphs", "len": "3"}, testRand(), testutils.TestLogger())
+	val, _ = g.Evaluate()
+	if n := len(strings.Split(val, "\n\n")); n != 3 {
+		t.Errorf("Expected 3 paragraphs, got %d: %q", n, val)
+	}
+}
+
+func TestKindGenerator_UnknownKind(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on unknown kind")
+		}
+	}()
+	NewKindGenerator(map[string]string{"kind": "nope"}, testRand(), testutils.TestLogger())
+}
+
+func TestStringFactory_Kind(t *testing.T) {
+	g := StringFactory{}.Create(map[string]string{"kind": "email"}, testRand(), testutils.TestLogger())
+	val, _ := g.EvaluateAny()
+	if !strings.Contains(val.(string), "@") {
+		t.Errorf("Expected an email, got %q", val)
+	}
+}
+
+func luhnValid(number string) bool {
+	sum := 0
+	for i := range number {
+		d, err := strconv.Atoi(number[len(number)-1-i : len(number)-i])
+		if err != nil {
+			return false
+		}
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return len(number) >= 15 && sum%10 == 0
+}
+
+// ibanValid checks an IBAN with the ISO 7064 mod 97-10 algorithm, computed digit by digit.
+func ibanValid(iban string) bool {
+	if len(iban) != 22 || !strings.HasPrefix(iban, "DE") {
+		return false
+	}
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		if c >= 'A' && c <= 'Z' {
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder == 1
+}
@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is used by string kinds if no locale is configured.
+const DefaultLocale = "en_US"
+
+var ErrUnknownLocale = errors.New("unknown locale provided")
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Locale holds locale specific data of string kinds.
+// Formats contain placeholders in braces, like "{first} {last}",
+// and digit placeholders: "#" is any digit, "%" is a digit from 2 to 9.
+//
+// Locales with gendered last names, like ru_RU, list male names in FirstNames and LastNames
+// and female ones in FemaleFirstNames and FemaleLastNames, so that full names agree in gender.
+type Locale struct {
+	FirstNames       []string `json:"first_names"`
+	FemaleFirstNames []string `json:"female_first_names"`
+	LastNames        []string `json:"last_names"`
+	FemaleLastNames  []string `json:"female_last_names"`
+	NameFormat       string   `json:"name_format"` // {first} and {last}
+	Cities           []string `json:"cities"`
+	Streets          []string `json:"streets"`
+	AddressFormat    string   `json:"address_format"` // {street} and {number}
+	PhoneFormat      string   `json:"phone_format"`
+	PostcodeFormat   string   `json:"postcode_format"`
+	DateFormat       string   `json:"date_format"`    // layout of time.Format
+	CompanyFormat    string   `json:"company_format"` // {last} and {suffix}
+	CompanySuffixes  []string `json:"company_suffixes"`
+}
+
+// Locales maps locale names, like "de_DE", to their data embedded from the locales directory.
+var Locales = loadLocales()
+
+// checkLocale returns ErrUnknownLocale listing the supported locales if the locale is not embedded.
+// An empty locale is DefaultLocale.
+func checkLocale(name string) error {
+	if _, ok := Locales[name]; ok || name == "" {
+		return nil
+	}
+	names := make([]string, 0, len(Locales))
+	for name := range Locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: %q, supported locales are %s", ErrUnknownLocale, name, strings.Join(names, ", "))
+}
+
+func loadLocales() map[string]*Locale {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	locales := make(map[string]*Locale, len(files))
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		locale := &Locale{}
+		if err := json.Unmarshal(data, locale); err != nil {
+			panic("invalid locale " + file.Name() + ": " + err.Error())
+		}
+		locales[strings.TrimSuffix(file.Name(), ".json")] = locale
+	}
+	return locales
+}
+
+// female reports whether a female name should be generated.
+// It is always false for locales with no separate female names.
+func (l *Locale) female(rand *rand.Rand) bool {
+	return len(l.FemaleFirstNames) > 0 && rand.Intn(2) == 0
+}
+
+func (l *Locale) firstName(rand *rand.Rand) string {
+	if l.female(rand) {
+		return pick(rand, l.FemaleFirstNames)
+	}
+	return pick(rand, l.FirstNames)
+}
+
+func (l *Locale) lastName(rand *rand.Rand) string {
+	if len(l.FemaleLastNames) > 0 && l.female(rand) {
+		return pick(rand, l.FemaleLastNames)
+	}
+	return pick(rand, l.LastNames)
+}
+
+func (l *Locale) name(rand *rand.Rand) string {
+	firstNames, lastNames := l.FirstNames, l.LastNames
+	if l.female(rand) {
+		firstNames = l.FemaleFirstNames
+		if len(l.FemaleLastNames) > 0 {
+			lastNames = l.FemaleLastNames
+		}
+	}
+	return expand(rand, l.NameFormat, map[string]func() string{
+		"first": func() string { return pick(rand, firstNames) },
+		"last":  func() string { return pick(rand, lastNames) },
+	})
+}
+
+func (l *Locale) address(rand *rand.Rand) string {
+	return expand(rand, l.AddressFormat, map[string]func() string{
+		"street": func() string { return pick(rand, l.Streets) },
+		"number": func() string { return strconv.Itoa(1 + rand.Intn(999)) },
+	})
+}
+
+func (l *Locale) company(rand *rand.Rand) string {
+	return expand(rand, l.CompanyFormat, map[string]func() string{
+		"last":   func() string { return pick(rand, l.LastNames) },
+		"suffix": func() string { return pick(rand, l.CompanySuffixes) },
+	})
+}
+
+// date returns a date from 1970 to 2030 formatted in the locale format.
+func (l *Locale) date(rand *rand.Rand) string {
+	from := time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)
+	days := int(to.Sub(from).Hours() / 24)
+	return from.AddDate(0, 0, rand.Intn(days)).Format(l.DateFormat)
+}
+
+// expand replaces placeholders in braces with values returned by their functions,
+// "#" with a random digit and "%" with a random digit from 2 to 9.
+// Every occurrence of a placeholder gets its own value.
+func expand(rand *rand.Rand, format string, values map[string]func() string) string {
+	var result strings.Builder
+	for i := 0; i < len(format); i++ {
+		switch format[i] {
+		case '#':
+			result.WriteByte(byte('0' + rand.Intn(10)))
+		case '%':
+			result.WriteByte(byte('2' + rand.Intn(8)))
+		case '{':
+			if end := strings.IndexByte(format[i:], '}'); end > 0 {
+				if value, ok := values[format[i+1:i+end]]; ok {
+					result.WriteString(value())
+					i += end
+					continue
+				}
+			}
+			result.WriteByte(format[i])
+		default:
+			result.WriteByte(format[i])
+		}
+	}
+	return result.String()
+}
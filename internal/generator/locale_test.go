@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestLocales_Complete(t *testing.T) {
+	for _, name := range []string{"en_US", "de_DE", "ru_RU", "fr_FR", "ja_JP"} {
+		l, ok := Locales[name]
+		if !ok {
+			t.Errorf("Locale %s is not embedded", name)
+			continue
+		}
+		if len(l.FirstNames) == 0 || len(l.LastNames) == 0 || len(l.Cities) == 0 || len(l.Streets) == 0 || len(l.CompanySuffixes) == 0 {
+			t.Errorf("Locale %s has empty word lists", name)
+		}
+		if l.NameFormat == "" || l.AddressFormat == "" || l.PhoneFormat == "" || l.PostcodeFormat == "" || l.DateFormat == "" || l.CompanyFormat == "" {
+			t.Errorf("Locale %s has empty formats", name)
+		}
+	}
+}
+
+func TestKindGenerator_Locale(t *testing.T) {
+	tests := []struct {
+		locale   string
+		kind     string
+		pattern  string
+		nonASCII bool // the kind is expected to produce non-ASCII strings
+	}{
+		{"de_DE", "postcode", `^[2-9]\d{4}$`, false},
+		{"ru_RU", "postcode", `^[2-9]\d{5}$`, false},
+		{"ja_JP", "postcode", `^\d{3}-\d{4}$`, false},
+		{"de_DE", "phone", `^\+49 [2-9]\d\d \d{7}$`, false},
+		{"ru_RU", "phone", `^\+7 \(9\d\d\) \d{3}-\d\d-\d\d$`, false},
+		{"fr_FR", "date", `^\d\d/\d\d/\d{4}$`, false},
+		{"ja_JP", "date", `^\d{4}/\d\d/\d\d$`, false},
+		{"de_DE", "address", `^\D+ \d+$`, false},
+		{"ru_RU", "name", `^\p{Cyrillic}+ \p{Cyrillic}+$`, true},
+		{"ja_JP", "name", `^\p{Han}+ [\p{Han}\p{Hiragana}]+$`, true},
+		{"ja_JP", "email", `^[a-z.0-9]+@`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.kind, func(t *testing.T) {
+			g := NewKindGenerator(map[string]string{"kind": tt.kind, "locale": tt.locale}, testRand(), testutils.TestLogger())
+			pattern := regexp.MustCompile(tt.pattern)
+			for range 20 {
+				val, _ := g.Evaluate()
+				if !pattern.MatchString(val) || !utf8.ValidString(val) {
+					t.Fatalf("Unexpected %s %s: %q", tt.locale, tt.kind, val)
+				}
+				if tt.nonASCII && !strings.ContainsFunc(val, func(r rune) bool { return r > 127 }) {
+					t.Fatalf("Expected non-ASCII %s %s: %q", tt.locale, tt.kind, val)
+				}
+			}
+		})
+	}
+}
+
+func TestKindGenerator_UnknownLocale(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on unknown locale")
+		}
+	}()
+	NewKindGenerator(map[string]string{"kind": "name", "locale": "xx_XX"}, testRand(), testutils.TestLogger())
+}
+
+func TestBuilder_UnknownLocale(t *testing.T) {
+	city := &typeinfo.Type{Kind: typeinfo.String}
+	typ := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Address", Fields: []typeinfo.Field{
+		{Name: "City", Type: city, MockTags: map[string]string{"kind": "city", "locale": "xx_XX"}},
+	}}
+	if _, err := testBuilder(5).Build(typ, nil); !errors.Is(err, ErrUnknownLocale) || !strings.Contains(err.Error(), "de_DE, en_US") {
+		t.Errorf("Build() error = %v, want ErrUnknownLocale listing the locales", err)
+	}
+
+	builder := NewBuilder(Options{MaxDepth: 5, Locale: "xx_XX"}, NewRandSource(1), testutils.TestLogger())
+	if _, err := builder.Build(city, map[string]string{"kind": "city"}); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("Build() error = %v, want ErrUnknownLocale", err)
+	}
+}
+
+func TestLocale_Date(t *testing.T) {
+	val := Locales["de_DE"].date(testRand())
+	if _, err := time.Parse("02.01.2006", val); err != nil {
+		t.Errorf("Date %q does not match the locale format: %v", val, err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	got := expand(testRand(), "{a}-{a} {b} {unknown} #%", map[string]func() string{
+		"a": func() string { return "x" },
+		"b": func() string { return "y" },
+	})
+	if !regexp.MustCompile(`^x-x y \{unknown\} \d[2-9]$`).MatchString(got) {
+		t.Errorf("expand() = %q", got)
+	}
+}
+
+func TestLocale_NameGender(t *testing.T) {
+	ru := Locales["ru_RU"]
+	rand := testRand()
+	for range 50 {
+		first, last, _ := strings.Cut(ru.name(rand), " ")
+		if slices.Contains(ru.FemaleFirstNames, first) != slices.Contains(ru.FemaleLastNames, last) {
+			t.Fatalf("Name %s %s does not agree in gender", first, last)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+)
+
+// maxKeyAttempts is the number of attempts to generate
+// a key that is not yet present in the map.
+const maxKeyAttempts = 100
+
+// MapEntry is a generated key-value pair of a map.
+type MapEntry struct {
+	Key   any
+	Value any
+}
+
+// MapGenerator generates maps with unique keys.
+// Entries are kept in generation order, so the output is reproducible.
+type MapGenerator struct {
+	key    AnyGenerator
+	value  AnyGenerator
+	minLen int
+	maxLen int
+	BaseGenerator
+}
+
+// NewMapGenerator creates a new MapGenerator using "minlen" and "maxlen" tags
+// for the number of entries. Defaults to 1 and 5 entries if not provided.
+func NewMapGenerator(key, value AnyGenerator, tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[[]MapEntry] {
+	minLen, maxLen := parseLenRange(tags, logger)
+	logger.Debug("MapGenerator created", "minlen", minLen, "maxlen", maxLen)
+	return &MapGenerator{key, value, minLen, maxLen, BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns map entries with unique keys.
+// If the key generator can not produce enough distinct keys,
+// the map is returned with fewer entries.
+func (g *MapGenerator) Evaluate() ([]MapEntry, error) {
+	length := g.minLen + g.rand.Intn(g.maxLen-g.minLen+1)
+	result := make([]MapEntry, 0, length)
+	seen := make(map[string]struct{}, length)
+
+	for len(result) < length {
+		key, err := g.uniqueKey(seen)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			g.logger.Warn("Key space exhausted, generating a smaller map", "wantLen", length, "len", len(result))
+			break
+		}
+
+		value, err := g.value.EvaluateAny()
+		if err != nil {
+			g.logger.Error("Failed to evaluate value generator", "key", key, "error", err)
+			return nil, fmt.Errorf("value of key %v: %w", key, err)
+		}
+		result = append(result, MapEntry{key, value})
+	}
+
+	g.logger.Debug("Evaluate generated map", "len", len(result))
+	return result, nil
+}
+
+// uniqueKey generates a key not present in seen and adds it there.
+// Keys are compared by their contents, like struct keys generated as records.
+// It returns nil if no such key was generated in maxKeyAttempts.
+func (g *MapGenerator) uniqueKey(seen map[string]struct{}) (any, error) {
+	for attempt := 0; attempt < maxKeyAttempts; attempt++ {
+		key, err := g.key.EvaluateAny()
+		if err != nil {
+			g.logger.Error("Failed to evaluate key generator", "error", err)
+			return nil, fmt.Errorf("key: %w", err)
+		}
+		if key == nil {
+			return nil, nil
+		}
+		var seenKey strings.Builder
+		writeKey(&seenKey, key)
+		if _, ok := seen[seenKey.String()]; ok {
+			continue
+		}
+		seen[seenKey.String()] = struct{}{}
+		return key, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestBuilder_MapKeyValueTags(t *testing.T) {
+	typ := &typeinfo.Type{Kind: typeinfo.Map, Key: &typeinfo.Type{Kind: typeinfo.String}, Elem: &typeinfo.Type{Kind: typeinfo.Uint8}}
+	tags := map[string]string{
+		"minlen":      "3",
+		"maxlen":      "6",
+		"keys.prefix": "attr_",
+		"keys.len":    "1",
+		"values.min":  "1",
+		"values.max":  "5",
+	}
+
+	g, err := testBuilder(5).Build(typ, tags)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		val, _ := g.EvaluateAny()
+		entries := val.([]MapEntry)
+		if len(entries) < 3 || len(entries) > 6 {
+			t.Fatalf("Length %d out of range [3,6]", len(entries))
+		}
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			key := entry.Key.(string)
+			if len(key) != len("attr_")+1 || key[:5] != "attr_" {
+				t.Errorf("Unexpected key: %s", key)
+			}
+			if seen[key] {
+				t.Errorf("Duplicate key: %s", key)
+			}
+			seen[key] = true
+			if v := entry.Value.(uint8); v < 1 || v > 5 {
+				t.Errorf("Value %d out of range [1,5]", v)
+			}
+		}
+	}
+}
+
+func TestMapGenerator_KeySpaceExhausted(t *testing.T) {
+	key := &GenericGenerator[int8]{impl: NewSignedGenerator[int8](map[string]string{"min": "1", "max": "2"}, testRand(), testutils.TestLogger())}
+	value := &GenericGenerator[string]{impl: NewStringGenerator(map[string]string{}, testRand(), testutils.TestLogger())}
+	g := NewMapGenerator(key, value, map[string]string{"minlen": "5"}, testRand(), testutils.TestLogger())
+
+	entries, err := g.Evaluate()
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 unique keys, got %d", len(entries))
+	}
+}
+
+func TestMapGenerator_StructKeys(t *testing.T) {
+	point := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Point", Fields: []typeinfo.Field{
+		{Name: "X", Type: &typeinfo.Type{Kind: typeinfo.Int}, MockTags: map[string]string{"min": "1", "max": "2"}},
+	}}
+	typ := &typeinfo.Type{Kind: typeinfo.Map, Key: point, Elem: &typeinfo.Type{Kind: typeinfo.String}}
+	g, err := testBuilder(5).Build(typ, map[string]string{"minlen": "5", "maxlen": "5"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	val, err := g.EvaluateAny()
+	if err != nil {
+		t.Fatalf("EvaluateAny() error = %v", err)
+	}
+	if entries := val.([]MapEntry); len(entries) != 2 {
+		t.Errorf("Expected 2 keys with distinct contents, got %d", len(entries))
+	}
+}
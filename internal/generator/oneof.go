@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// OneofGenerator chooses values from a fixed set, optionally weighted.
+type OneofGenerator struct {
+	values     []any
+	cumulative []int // cumulative weights of values
+	BaseGenerator
+}
+
+// NewOneofGenerator creates a new OneofGenerator choosing from values of a basic kind,
+// e.g. "active" and "deleted" for strings or "1" and "2" for integers.
+// Nil weights give every value the same probability.
+// It panics on values that are not valid for the kind and on unsupported kinds.
+func NewOneofGenerator(kind typeinfo.Kind, values []string, weights []int, rand *rand.Rand, logger *slog.Logger) Generator[any] {
+	g := &OneofGenerator{BaseGenerator: BaseGenerator{rand, logger}}
+	total := 0
+	for i, value := range values {
+		parsed, err := parseKindValue(kind, value)
+		if err != nil {
+			logger.Error("Failed to parse oneof value", "kind", kind, "value", value, "error", err)
+			panic(err)
+		}
+		weight := 1
+		if weights != nil {
+			weight = weights[i]
+		}
+		total += weight
+		g.values = append(g.values, parsed)
+		g.cumulative = append(g.cumulative, total)
+	}
+	if total == 0 {
+		logger.Error("No oneof values with a positive weight provided", "values", values)
+		panic("no oneof values with a positive weight provided")
+	}
+
+	logger.Debug("OneofGenerator created", "kind", kind, "values", values, "weights", weights)
+	return g
+}
+
+// Evaluate returns one of the values, chosen according to their weights.
+func (g *OneofGenerator) Evaluate() (any, error) {
+	n := g.rand.Intn(g.cumulative[len(g.cumulative)-1])
+	result := g.values[sort.SearchInts(g.cumulative, n+1)]
+	g.logger.Debug("Evaluate generated value", "value", result)
+	return result, nil
+}
+
+// parseOneof parses a "oneof" tag into values and weights.
+// Values are separated by commas and can have integer weights after a colon,
+// e.g. "active:70,suspended:20,deleted:10". Values without a weight have the weight 1.
+// It panics on negative weights.
+func parseOneof(tag string) (values []string, weights []int) {
+	for _, part := range strings.Split(tag, ",") {
+		weight := 1
+		if i := strings.LastIndexByte(part, ':'); i >= 0 {
+			if w, err := strconv.Atoi(part[i+1:]); err == nil {
+				if w < 0 {
+					panic(fmt.Sprintf("invalid oneof weight provided: %d", w))
+				}
+				part, weight = part[:i], w
+			}
+		}
+		values = append(values, part)
+		weights = append(weights, weight)
+	}
+	return values, weights
+}
+
+// parseKindValue parses a string into a value of the Go type of a basic kind, e.g. int16 for typeinfo.Int16.
+func parseKindValue(kind typeinfo.Kind, value string) (any, error) {
+	switch kind {
+	case typeinfo.String:
+		return value, nil
+	case typeinfo.Bool:
+		return strconv.ParseBool(value)
+	case typeinfo.Int, typeinfo.Int8, typeinfo.Int16, typeinfo.Int32, typeinfo.Int64:
+		v, err := strconv.ParseInt(value, 0, kindBits(kind))
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case typeinfo.Int8:
+			return int8(v), nil
+		case typeinfo.Int16:
+			return int16(v), nil
+		case typeinfo.Int32:
+			return int32(v), nil
+		case typeinfo.Int64:
+			return v, nil
+		}
+		return int(v), nil
+	case typeinfo.Uint, typeinfo.Uint8, typeinfo.Uint16, typeinfo.Uint32, typeinfo.Uint64, typeinfo.Uintptr:
+		v, err := strconv.ParseUint(value, 0, kindBits(kind))
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case typeinfo.Uint8:
+			return uint8(v), nil
+		case typeinfo.Uint16:
+			return uint16(v), nil
+		case typeinfo.Uint32:
+			return uint32(v), nil
+		case typeinfo.Uint64:
+			return v, nil
+		case typeinfo.Uintptr:
+			return uintptr(v), nil
+		}
+		return uint(v), nil
+	case typeinfo.Float32:
+		v, err := strconv.ParseFloat(value, 32)
+		return float32(v), err
+	case typeinfo.Float64:
+		return strconv.ParseFloat(value, 64)
+	case typeinfo.Complex64:
+		v, err := strconv.ParseComplex(value, 64)
+		return complex64(v), err
+	case typeinfo.Complex128:
+		return strconv.ParseComplex(value, 128)
+	}
+	return nil, fmt.Errorf("oneof values are not supported for %s", kind)
+}
+
+// kindBits returns the size in bits of an integer kind.
+func kindBits(kind typeinfo.Kind) int {
+	switch kind {
+	case typeinfo.Int8, typeinfo.Uint8:
+		return 8
+	case typeinfo.Int16, typeinfo.Uint16:
+		return 16
+	case typeinfo.Int32, typeinfo.Uint32:
+		return 32
+	case typeinfo.Int64, typeinfo.Uint64:
+		return 64
+	}
+	return strconv.IntSize
+}
+
+func (g *OneofGenerator) Validate() error {
+	return nil
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestParseOneof(t *testing.T) {
+	values, weights := parseOneof("active:70,suspended:20,deleted,12:30:1")
+	if want := []string{"active", "suspended", "deleted", "12:30"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if want := []int{70, 20, 1, 1}; !reflect.DeepEqual(weights, want) {
+		t.Errorf("weights = %v, want %v", weights, want)
+	}
+}
+
+func TestOneofGenerator_Weights(t *testing.T) {
+	g := NewOneofGenerator(typeinfo.String, []string{"a", "b", "c"}, []int{80, 20, 0}, testRand(), testutils.TestLogger())
+	counts := make(map[any]int)
+	for range 10000 {
+		val, _ := g.Evaluate()
+		counts[val]++
+	}
+	if counts["c"] != 0 || counts["a"] < 7500 || counts["a"] > 8500 {
+		t.Errorf("Unexpected distribution: %v", counts)
+	}
+}
+
+func TestOneofGenerator_Kinds(t *testing.T) {
+	tests := []struct {
+		kind  typeinfo.Kind
+		value string
+		want  any
+	}{
+		{typeinfo.Int, "-3", -3},
+		{typeinfo.Int8, "127", int8(127)},
+		{typeinfo.Uint16, "0x10", uint16(16)},
+		{typeinfo.Float32, "0.5", float32(0.5)},
+		{typeinfo.Float64, "1e3", 1000.0},
+		{typeinfo.Bool, "true", true},
+		{typeinfo.String, "x", "x"},
+	}
+	for _, tt := range tests {
+		g := NewOneofGenerator(tt.kind, []string{tt.value}, nil, testRand(), testutils.TestLogger())
+		if val, _ := g.Evaluate(); val != tt.want {
+			t.Errorf("%s %q = %#v, want %#v", tt.kind, tt.value, val, tt.want)
+		}
+	}
+}
+
+func TestOneofGenerator_Invalid(t *testing.T) {
+	tests := []struct {
+		kind    typeinfo.Kind
+		values  []string
+		weights []int
+	}{
+		{typeinfo.Int8, []string{"128"}, nil},
+		{typeinfo.Int, []string{"abc"}, nil},
+		{typeinfo.Struct, []string{"a"}, nil},
+		{typeinfo.String, []string{"a"}, []int{0}},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for %s %v", tt.kind, tt.values)
+				}
+			}()
+			NewOneofGenerator(tt.kind, tt.values, tt.weights, testRand(), testutils.TestLogger())
+		}()
+	}
+}
@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+)
+
+const defaultMaxRepeat = 5
+
+// PatternGenerator generates strings matching a regular expression.
+type PatternGenerator struct {
+	regexp    *syntax.Regexp
+	maxRepeat int // maximum number of repetitions of unbounded operators like "*" and "+"
+	prefix    string
+	suffix    string
+	BaseGenerator
+}
+
+// NewPatternGenerator creates a new PatternGenerator using "pattern", "maxrepeat", "prefix" and "suffix" tags.
+// The pattern uses Go regexp syntax and is implicitly anchored, e.g. `pattern=[A-Z]{3}-\d{4}`.
+// Unbounded repetitions like "*", "+" and "{2,}" repeat at most "maxrepeat" times above their minimum, 5 by default.
+// It panics on invalid patterns.
+func NewPatternGenerator(tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[string] {
+	re, err := syntax.Parse(tags["pattern"], syntax.Perl)
+	if err != nil {
+		logger.Error("Failed to parse pattern tag", "pattern", tags["pattern"], "error", err)
+		panic(err)
+	}
+	if matchesNothing(re) {
+		logger.Error("Pattern matches no strings", "pattern", tags["pattern"])
+		panic(fmt.Sprintf("pattern matches no strings: %s", tags["pattern"]))
+	}
+
+	maxRepeat := defaultMaxRepeat
+	if value, ok := tags["maxrepeat"]; ok {
+		maxRepeat, err = strconv.Atoi(value)
+		if err != nil {
+			logger.Error("Failed to parse maxrepeat tag", "maxrepeat", value, "error", err)
+			panic(err)
+		}
+		if maxRepeat < 0 {
+			logger.Error("Invalid maxrepeat provided", "maxrepeat", maxRepeat)
+			panic(fmt.Sprintf("invalid maxrepeat provided: %d", maxRepeat))
+		}
+	}
+
+	logger.Debug("PatternGenerator created", "pattern", re, "maxRepeat", maxRepeat, "prefix", tags["prefix"], "suffix", tags["suffix"])
+	return &PatternGenerator{re, maxRepeat, tags["prefix"], tags["suffix"], BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns a string matching the pattern including prefix and suffix.
+func (g *PatternGenerator) Evaluate() (string, error) {
+	var result strings.Builder
+	result.WriteString(g.prefix)
+	g.write(&result, g.regexp)
+	result.WriteString(g.suffix)
+	g.logger.Debug("Evaluate generated value", "value", result.String())
+	return result.String(), nil
+}
+
+// write appends a random string matching re to out.
+// Assertions like "^", "$" and "\b" produce nothing.
+func (g *PatternGenerator) write(out *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && g.rand.Intn(2) == 0 {
+				r = swapASCIICase(r)
+			}
+			out.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		out.WriteRune(g.classRune(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		out.WriteRune(rune(' ' + g.rand.Intn('~'-' '+1)))
+	case syntax.OpCapture:
+		g.write(out, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.write(out, sub)
+		}
+	case syntax.OpAlternate:
+		g.write(out, re.Sub[g.rand.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := g.repeatRange(re)
+		for n := lo + g.rand.Intn(hi-lo+1); n > 0; n-- {
+			g.write(out, re.Sub[0])
+		}
+	}
+}
+
+// swapASCIICase returns the other case of an ASCII letter and any other rune as is,
+// so that case-insensitive literals do not produce folds like the Kelvin sign for "k".
+func swapASCIICase(r rune) rune {
+	switch {
+	case 'a' <= r && r <= 'z':
+		return r - 'a' + 'A'
+	case 'A' <= r && r <= 'Z':
+		return r - 'A' + 'a'
+	}
+	return r
+}
+
+// repeatRange returns the number of repetitions of a repeat operator.
+func (g *PatternGenerator) repeatRange(re *syntax.Regexp) (lo, hi int) {
+	switch re.Op {
+	case syntax.OpStar:
+		return 0, g.maxRepeat
+	case syntax.OpPlus:
+		return 1, 1 + g.maxRepeat
+	case syntax.OpQuest:
+		return 0, 1
+	}
+	if re.Max < 0 {
+		return re.Min, re.Min + g.maxRepeat
+	}
+	return re.Min, re.Max
+}
+
+// classRune returns a random rune of a character class given by pairs of inclusive ranges.
+// Printable ASCII runes are preferred, so that classes like [^a-z] or \D produce readable strings.
+func (g *PatternGenerator) classRune(ranges []rune) rune {
+	if printable := intersectRanges(ranges, ' ', '~'); len(printable) > 0 {
+		ranges = printable
+	}
+	total := 0
+	for i := 0; i < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	n := g.rand.Intn(total)
+	for i := 0; i < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[0]
+}
+
+// intersectRanges returns the parts of the ranges within [lo, hi].
+func intersectRanges(ranges []rune, lo, hi rune) []rune {
+	var result []rune
+	for i := 0; i < len(ranges); i += 2 {
+		from, to := max(ranges[i], lo), min(ranges[i+1], hi)
+		if from <= to {
+			result = append(result, from, to)
+		}
+	}
+	return result
+}
+
+// matchesNothing reports whether the regexp or any of its subexpressions can not match,
+// like an empty character class.
+func matchesNothing(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpNoMatch || re.Op == syntax.OpCharClass && len(re.Rune) == 0 {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if matchesNothing(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *PatternGenerator) Validate() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+)
+
+func TestPatternGenerator_Matches(t *testing.T) {
+	patterns := []string{
+		`[A-Z]{3}-\d{4}`,
+		`ORD-[0-9]{8}`,
+		`^[A-Z]{2}\d{2} ?[A-Z]{3}$`,
+		`(foo|bar)+_[a-f0-9]*`,
+		`(?i)sku-\w{5,}`,
+		`[^a-z]{3}\.\D\s?`,
+		`a.b`,
+		`\p{Greek}{2,4}`,
+		`x{0}y?`,
+	}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			g := NewPatternGenerator(map[string]string{"pattern": pattern}, testRand(), testutils.TestLogger())
+			re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+			for range 100 {
+				val, _ := g.Evaluate()
+				if !re.MatchString(val) {
+					t.Fatalf("Value %q does not match %s", val, pattern)
+				}
+			}
+		})
+	}
+}
+
+func TestPatternGenerator_MaxRepeat(t *testing.T) {
+	g := NewPatternGenerator(map[string]string{"pattern": `a+b*`, "maxrepeat": "2", "prefix": "<", "suffix": ">"}, testRand(), testutils.TestLogger())
+	re := regexp.MustCompile(`^<a{1,3}b{0,2}>$`)
+	for range 100 {
+		val, _ := g.Evaluate()
+		if !re.MatchString(val) {
+			t.Fatalf("Value %q exceeds maxrepeat", val)
+		}
+	}
+}
+
+func TestPatternGenerator_FoldCase(t *testing.T) {
+	g := NewPatternGenerator(map[string]string{"pattern": `(?i)kiss-\d`}, testRand(), testutils.TestLogger())
+	re := regexp.MustCompile(`^[kK][iI][sS][sS]-[0-9]$`)
+	seen := make(map[string]bool)
+	for range 100 {
+		val, _ := g.Evaluate()
+		if !re.MatchString(val) {
+			t.Fatalf("Value %q is not folded within ASCII", val)
+		}
+		seen[val[:1]] = true
+	}
+	if !seen["k"] || !seen["K"] {
+		t.Errorf("Both cases are expected, got %v", seen)
+	}
+}
+
+func TestPatternGenerator_Deterministic(t *testing.T) {
+	a := NewPatternGenerator(map[string]string{"pattern": `[a-z0-9]{16}`}, testRand(), testutils.TestLogger())
+	b := NewPatternGenerator(map[string]string{"pattern": `[a-z0-9]{16}`}, testRand(), testutils.TestLogger())
+	for range 10 {
+		x, _ := a.Evaluate()
+		y, _ := b.Evaluate()
+		if x != y {
+			t.Fatalf("Same seed produced %q and %q", x, y)
+		}
+	}
+}
+
+func TestPatternGenerator_Invalid(t *testing.T) {
+	for _, tags := range []map[string]string{
+		{"pattern": `[a-`},
+		{"pattern": `[^\x00-\x{10FFFF}]`},
+		{"pattern": `a*`, "maxrepeat": "-1"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for %v", tags)
+				}
+			}()
+			NewPatternGenerator(tags, testRand(), testutils.TestLogger())
+		}()
+	}
+}
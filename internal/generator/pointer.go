@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+)
+
+// PointerGenerator generates pointer values.
+// A nil pointer is represented by a nil value,
+// otherwise the value of the element generator is returned.
+type PointerGenerator struct {
+	elem     AnyGenerator
+	nullable float64
+	BaseGenerator
+}
+
+// NewPointerGenerator creates a new PointerGenerator using the "nullable" tag,
+// the probability of generating a nil pointer. Defaults to defaultNullable if not provided.
+func NewPointerGenerator(elem AnyGenerator, tags map[string]string, defaultNullable float64, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	nullable := defaultNullable
+	if value, ok := tags["nullable"]; ok {
+		p, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			logger.Error("Failed to parse nullable tag", "nullable", value, "error", err)
+			panic(err)
+		}
+		if p < 0 || p > 1 {
+			logger.Error("Invalid nullable probability provided", "nullable", p)
+			panic(fmt.Sprintf("invalid nullable probability provided: %v", p))
+		}
+		nullable = p
+	}
+
+	logger.Debug("PointerGenerator created", "nullable", nullable)
+	return &PointerGenerator{elem, nullable, BaseGenerator{rand, logger}}
+}
+
+// EvaluateAny returns nil with the configured probability
+// and the value of the element generator otherwise.
+func (g *PointerGenerator) EvaluateAny() (any, error) {
+	if g.nullable > 0 && g.rand.Float64() < g.nullable {
+		g.logger.Debug("Evaluate generated nil pointer")
+		return nil, nil
+	}
+	return g.elem.EvaluateAny()
+}
+
+// bounded returns a generator of nil pointers and element values within bounds,
+// or nil if the range of the element generator can not be narrowed.
+func (g *PointerGenerator) bounded() boundedGenerator {
+	elem := asBounded(g.elem)
+	if elem == nil {
+		return nil
+	}
+	return boundedFunc(func(low, high any) (any, bool, error) {
+		if g.nullable > 0 && g.rand.Float64() < g.nullable {
+			g.logger.Debug("Evaluate generated nil pointer")
+			return nil, true, nil
+		}
+		return elem.evaluateWithin(low, high)
+	})
+}
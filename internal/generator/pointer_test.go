@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestPointerGenerator_Nullable(t *testing.T) {
+	elem := &GenericGenerator[string]{impl: NewStringGenerator(map[string]string{}, testRand(), testutils.TestLogger())}
+
+	tests := []struct {
+		name            string
+		tags            map[string]string
+		defaultNullable float64
+		minNil, maxNil  int
+	}{
+		{name: "never nil", tags: map[string]string{}, minNil: 0, maxNil: 0},
+		{name: "always nil", tags: map[string]string{"nullable": "1"}, minNil: 1000, maxNil: 1000},
+		{name: "tag", tags: map[string]string{"nullable": "0.3"}, minNil: 200, maxNil: 400},
+		{name: "global default", tags: map[string]string{}, defaultNullable: 0.5, minNil: 400, maxNil: 600},
+		{name: "tag overrides default", tags: map[string]string{"nullable": "0"}, defaultNullable: 0.5, minNil: 0, maxNil: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewPointerGenerator(elem, tt.tags, tt.defaultNullable, testRand(), testutils.TestLogger())
+			nils := 0
+			for i := 0; i < 1000; i++ {
+				val, _ := g.EvaluateAny()
+				if val == nil {
+					nils++
+				}
+			}
+			if nils < tt.minNil || nils > tt.maxNil {
+				t.Errorf("Got %d nil values of 1000, want [%d,%d]", nils, tt.minNil, tt.maxNil)
+			}
+		})
+	}
+}
+
+func TestBuilder_PointerToPointer(t *testing.T) {
+	typ := &typeinfo.Type{Kind: typeinfo.Pointer, Elem: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: &typeinfo.Type{Kind: typeinfo.Int64}}}
+	g, err := testBuilder(5).Build(typ, map[string]string{"nullable": "0", "min": "5", "max": "5"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	val, _ := g.EvaluateAny()
+	if val != int64(5) {
+		t.Errorf("Expected pointed-to value 5, got %v", val)
+	}
+}
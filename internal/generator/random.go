@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+)
+
+// RandSource derives independent random streams from a single run seed.
+// Every generator gets its own stream identified by a path, like "User.Address.City",
+// so adding a field does not change the values generated for other fields.
+type RandSource struct {
+	seed int64
+}
+
+// NewRandSource creates a new RandSource for the run seed.
+func NewRandSource(seed int64) *RandSource {
+	return &RandSource{seed: seed}
+}
+
+// Seed returns the run seed.
+func (s *RandSource) Seed() int64 {
+	return s.seed
+}
+
+// Rand returns a random stream for the given path.
+// The same seed and path always produce the same stream.
+func (s *RandSource) Rand(path ...string) *rand.Rand {
+	h := fnv.New64a()
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], uint64(s.seed))
+	h.Write(seed[:])
+	for _, part := range path {
+		h.Write([]byte(part))
+		h.Write([]byte{0}) // separator, so that ("ab", "c") differs from ("a", "bc")
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestRandSource_Streams(t *testing.T) {
+	a := NewRandSource(42).Rand("User", "ID").Int63()
+	b := NewRandSource(42).Rand("User", "ID").Int63()
+	if a != b {
+		t.Errorf("Same seed and path produced different values: %d != %d", a, b)
+	}
+	if c := NewRandSource(42).Rand("User", "Name").Int63(); c == a {
+		t.Errorf("Different paths produced the same value: %d", c)
+	}
+	if d := NewRandSource(43).Rand("User", "ID").Int63(); d == a {
+		t.Errorf("Different seeds produced the same value: %d", d)
+	}
+	if e := NewRandSource(42).Rand("Use", "rID").Int63(); e == a {
+		t.Errorf("Paths with the same concatenation produced the same value: %d", e)
+	}
+}
+
+func TestBuilder_Reproducibility(t *testing.T) {
+	id := typeinfo.Field{Name: "ID", Type: &typeinfo.Type{Kind: typeinfo.Int64}}
+	name := typeinfo.Field{Name: "Name", Type: &typeinfo.Type{Kind: typeinfo.String}}
+	user := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{id}}
+	extendedUser := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{name, id}}
+
+	evaluate := func(typ *typeinfo.Type, count int) []*Record {
+		g, err := testBuilder(5).Build(typ, nil)
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		records := make([]*Record, count)
+		for i := range records {
+			val, _ := g.EvaluateAny()
+			records[i] = val.(*Record)
+		}
+		return records
+	}
+
+	first := evaluate(user, 3)
+	second := evaluate(user, 3)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Same seed produced different records")
+	}
+	if first[0].Fields[0].Value == first[1].Fields[0].Value {
+		t.Errorf("Instances are identical: %v", first[0].Fields[0].Value)
+	}
+
+	extended := evaluate(extendedUser, 3)
+	for i := range first {
+		if first[i].Fields[0].Value != extended[i].Fields[1].Value {
+			t.Errorf("Adding a field changed the value of ID in instance %d", i)
+		}
+	}
+}
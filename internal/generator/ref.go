@@ -0,0 +1,276 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+var (
+	ErrRefCycle     = errors.New("cyclic struct references")
+	ErrRefUnknown   = errors.New("unknown reference")
+	ErrRefExhausted = errors.New("all referenced values are used")
+)
+
+// RefPool collects values of struct fields referenced by "ref" tags, like the IDs of users for "ref=User.ID",
+// while the structs of a run are generated. Structs have to be generated in the order returned by Order.
+type RefPool struct {
+	structs map[string]*typeinfo.Type
+	refs    map[string][]string // referenced field paths by struct name, e.g. "ID" of "User"
+	deps    map[string][]string // names of referenced structs by the name of the referencing struct
+	values  map[string][]any    // generated values by reference, e.g. "User.ID"
+	picks   []*refPick          // one-to-one values chosen for the instance being generated
+}
+
+// refPick is a value of a one-to-one reference chosen by a RefGenerator.
+// It is returned to the generator if the value chosen with it is discarded before the instance is collected.
+type refPick struct {
+	gen      *RefGenerator
+	index    int  // index of the value in the pool
+	released bool // whether the value is returned to the generator
+}
+
+// NewRefPool creates a RefPool for the structs of a run, keyed by their names.
+// It collects the references of all fields, including fields of nested structs, slice elements and map values.
+func NewRefPool(structs map[string]*typeinfo.Type) *RefPool {
+	p := &RefPool{
+		structs: structs,
+		refs:    make(map[string][]string),
+		deps:    make(map[string][]string),
+		values:  make(map[string][]any),
+	}
+	for name, t := range structs {
+		for _, ref := range collectRefs(t, nil, nil) {
+			target, path, _ := strings.Cut(ref, ".")
+			if !slices.Contains(p.refs[target], path) {
+				p.refs[target] = append(p.refs[target], path)
+			}
+			if target != name && !slices.Contains(p.deps[name], target) {
+				p.deps[name] = append(p.deps[name], target)
+			}
+		}
+	}
+	return p
+}
+
+// collectRefs appends the "ref" tags of the fields of t and of its nested types.
+// visiting holds the structs being walked, so that recursive types are walked once.
+func collectRefs(t *typeinfo.Type, refs []string, visiting []*typeinfo.Type) []string {
+	if t == nil || slices.Contains(visiting, t) {
+		return refs
+	}
+	visiting = append(visiting, t)
+	for _, field := range t.Fields {
+		for key, value := range field.MockTags {
+			if key == "ref" || strings.HasSuffix(key, ".ref") {
+				refs = append(refs, value)
+			}
+		}
+		refs = collectRefs(field.Type, refs, visiting)
+	}
+	refs = collectRefs(t.Elem, refs, visiting)
+	return collectRefs(t.Key, refs, visiting)
+}
+
+// Order returns the names of the structs in an order where referenced structs come before the structs referencing them,
+// e.g. "User" before "Order" for "ref=User.ID". Structs keep the alphabetical order otherwise.
+// It fails on cyclic references between structs, references to a struct of the same type are allowed.
+func (p *RefPool) Order() ([]string, error) {
+	names := make([]string, 0, len(p.structs))
+	for name := range p.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	order := make([]string, 0, len(names))
+	state := make(map[string]int) // 0 - not visited, 1 - visiting, 2 - done
+	var stack []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			start := slices.Index(stack, name)
+			return fmt.Errorf("%w: %s", ErrRefCycle, strings.Join(append(slices.Clone(stack[start:]), name), " -> "))
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		stack = append(stack, name)
+		deps := slices.Clone(p.deps[name])
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := p.structs[dep]; !ok {
+				continue // reported when the referencing field is built
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = 2
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Resolve returns the type of a referenced field, like "User.ID".
+// It fails if the struct is not generated in the run or has no such field.
+func (p *RefPool) Resolve(ref string) (*typeinfo.Type, error) {
+	name, path, ok := strings.Cut(ref, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, expected a struct name and a field like User.ID", ErrRefUnknown, ref)
+	}
+	t, ok := p.structs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, struct %s is not generated", ErrRefUnknown, ref, name)
+	}
+	for _, part := range strings.Split(path, ".") {
+		t, _ = t.Deref()
+		i := slices.IndexFunc(t.Fields, func(f typeinfo.Field) bool { return f.Name == part })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s, %s has no field %s", ErrRefUnknown, ref, t, part)
+		}
+		t = t.Fields[i].Type
+	}
+	return t, nil
+}
+
+// Collect stores the values of the referenced fields of a generated instance of the struct.
+// Nil values, like nil pointers, are skipped. One-to-one values chosen for the instance stay used.
+func (p *RefPool) Collect(structName string, record *Record) {
+	p.picks = nil
+	for _, path := range p.refs[structName] {
+		if value, ok := record.Lookup(path); ok && value != nil {
+			ref := structName + "." + path
+			p.values[ref] = append(p.values[ref], value)
+		}
+	}
+}
+
+// Values returns the values of a referenced field generated so far.
+func (p *RefPool) Values(ref string) []any {
+	return p.values[ref]
+}
+
+// mark returns the number of one-to-one values chosen for the instance being generated,
+// the values chosen after it are returned by since. The pool may be nil.
+func (p *RefPool) mark() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.picks)
+}
+
+// since returns the one-to-one values chosen after the mark.
+func (p *RefPool) since(mark int) []*refPick {
+	if p == nil {
+		return nil
+	}
+	return p.picks[mark:len(p.picks):len(p.picks)]
+}
+
+// release returns one-to-one values to their generators when the values chosen with them are discarded,
+// like a field regenerated for a unique group or a value not satisfying a comparison.
+func release(picks []*refPick) {
+	for _, pick := range picks {
+		if !pick.released {
+			pick.released = true
+			pick.gen.free = append(pick.gen.free, pick.index)
+		}
+	}
+}
+
+// RefGenerator chooses values of a field of another struct generated in the same run,
+// like the IDs of users for "Order.UserID".
+type RefGenerator struct {
+	pool         *RefPool
+	ref          string
+	one          bool  // every value is chosen at most once
+	zipf         bool  // values are chosen with a Zipf distribution instead of uniformly
+	free         []int // indexes of values not chosen yet, for one-to-one references
+	seen         int   // number of values added to free
+	zipfN        int   // number of values of the zipf distribution
+	distribution *rand.Zipf
+	BaseGenerator
+}
+
+// NewRefGenerator creates a new RefGenerator using "ref", "cardinality" and "distribution" tags.
+// "cardinality=one" uses every referenced value at most once, like a one-to-one relation,
+// the default "cardinality=many" allows repeated values, like a one-to-many relation.
+// Repeated values are chosen with a "uniform" distribution or a "zipf" one, where a few values are used by most instances.
+// It panics on invalid tags.
+func NewRefGenerator(pool *RefPool, tags map[string]string, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	g := &RefGenerator{pool: pool, ref: tags["ref"], BaseGenerator: BaseGenerator{rand, logger}}
+	switch tags["cardinality"] {
+	case "", "many":
+	case "one":
+		g.one = true
+	default:
+		logger.Error("Invalid cardinality provided", "cardinality", tags["cardinality"])
+		panic(fmt.Sprintf("invalid cardinality provided: %s, expected one or many", tags["cardinality"]))
+	}
+	switch tags["distribution"] {
+	case "", "uniform":
+	case "zipf":
+		g.zipf = true
+	default:
+		logger.Error("Invalid distribution provided", "distribution", tags["distribution"])
+		panic(fmt.Sprintf("invalid distribution provided: %s, expected uniform or zipf", tags["distribution"]))
+	}
+	if g.one && g.zipf {
+		logger.Error("Distribution of one-to-one references provided", "distribution", tags["distribution"])
+		panic("distribution can not be combined with cardinality=one")
+	}
+
+	logger.Debug("RefGenerator created", "ref", g.ref, "one", g.one, "zipf", g.zipf)
+	return g
+}
+
+// EvaluateAny returns one of the referenced values generated so far.
+// It returns nil if there are none yet, like for the first instance of a struct referencing itself.
+func (g *RefGenerator) EvaluateAny() (any, error) {
+	values := g.pool.Values(g.ref)
+	if len(values) == 0 {
+		g.logger.Debug("No referenced values generated yet", "ref", g.ref)
+		return nil, nil
+	}
+
+	var result any
+	switch {
+	case g.one:
+		for ; g.seen < len(values); g.seen++ {
+			g.free = append(g.free, g.seen)
+		}
+		if len(g.free) == 0 {
+			g.logger.Error("All referenced values are used", "ref", g.ref, "count", len(values))
+			return nil, fmt.Errorf("%w: %d values of %s with cardinality=one", ErrRefExhausted, len(values), g.ref)
+		}
+		i := g.rand.Intn(len(g.free))
+		result = values[g.free[i]]
+		g.pool.picks = append(g.pool.picks, &refPick{gen: g, index: g.free[i]})
+		g.free[i] = g.free[len(g.free)-1]
+		g.free = g.free[:len(g.free)-1]
+	case g.zipf:
+		if g.zipfN != len(values) {
+			g.zipfN = len(values)
+			g.distribution = rand.NewZipf(g.rand, 1.1, 1, uint64(len(values)-1))
+		}
+		result = values[g.distribution.Uint64()]
+	default:
+		result = values[g.rand.Intn(len(values))]
+	}
+	g.logger.Debug("Evaluate generated value", "value", result)
+	return result, nil
+}
@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func refStructs() map[string]*typeinfo.Type {
+	intType := &typeinfo.Type{Kind: typeinfo.Int}
+	return map[string]*typeinfo.Type{
+		"User": {Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{
+			{Name: "ID", Type: intType},
+		}},
+		"Order": {Kind: typeinfo.Struct, Name: "Order", Fields: []typeinfo.Field{
+			{Name: "ID", Type: intType},
+			{Name: "UserID", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: intType}, MockTags: map[string]string{"ref": "User.ID"}},
+		}},
+		"Address": {Kind: typeinfo.Struct, Name: "Address", Fields: []typeinfo.Field{
+			{Name: "OrderIDs", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: intType}, MockTags: map[string]string{"elem.ref": "Order.ID"}},
+		}},
+		"Employee": {Kind: typeinfo.Struct, Name: "Employee", Fields: []typeinfo.Field{
+			{Name: "ID", Type: intType},
+			{Name: "ManagerID", Type: intType, MockTags: map[string]string{"ref": "Employee.ID"}},
+		}},
+	}
+}
+
+func TestRefPool_Order(t *testing.T) {
+	structs := refStructs()
+	order, err := NewRefPool(structs).Order()
+	if err != nil {
+		t.Fatalf("Order() error = %v", err)
+	}
+	if want := []string{"User", "Order", "Address", "Employee"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Order() = %v, want %v", order, want)
+	}
+
+	structs["User"].Fields = append(structs["User"].Fields, typeinfo.Field{
+		Name: "Home", Type: structs["Address"],
+	})
+	_, err = NewRefPool(structs).Order()
+	if !errors.Is(err, ErrRefCycle) || !strings.Contains(err.Error(), "Order -> User -> Order") {
+		t.Errorf("Order() error = %v, want a cycle through nested structs", err)
+	}
+}
+
+func TestRefPool_Resolve(t *testing.T) {
+	pool := NewRefPool(refStructs())
+	if typ, err := pool.Resolve("Order.UserID"); err != nil || typ.Kind != typeinfo.Pointer {
+		t.Errorf("Resolve() = %v, %v", typ, err)
+	}
+	for _, ref := range []string{"User", "Product.ID", "User.Name"} {
+		if _, err := pool.Resolve(ref); !errors.Is(err, ErrRefUnknown) {
+			t.Errorf("Resolve(%q) error = %v, want ErrRefUnknown", ref, err)
+		}
+	}
+}
+
+func TestRefGenerator(t *testing.T) {
+	pool := NewRefPool(refStructs())
+	for i := range 10 {
+		pool.Collect("User", &Record{Fields: []RecordField{{Field: typeinfo.Field{Name: "ID"}, Value: i}}})
+	}
+
+	tests := []struct {
+		name string
+		tags map[string]string
+		// maxCount is the maximum number of times a value is chosen in 1000 evaluations
+		maxCount int
+	}{
+		{"uniform", map[string]string{"ref": "User.ID"}, 150},
+		{"zipf", map[string]string{"ref": "User.ID", "distribution": "zipf"}, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewRefGenerator(pool, tt.tags, testRand(), testutils.TestLogger())
+			counts := make(map[any]int)
+			for range 1000 {
+				val, err := g.EvaluateAny()
+				if err != nil {
+					t.Fatalf("EvaluateAny() error = %v", err)
+				}
+				counts[val]++
+			}
+			for val, count := range counts {
+				if val.(int) < 0 || val.(int) > 9 || count > tt.maxCount {
+					t.Errorf("Unexpected distribution: %v", counts)
+				}
+			}
+			if tt.name == "zipf" && counts[0] < counts[9]*3 {
+				t.Errorf("First values should be chosen more often: %v", counts)
+			}
+		})
+	}
+
+	g := NewRefGenerator(pool, map[string]string{"ref": "User.ID", "cardinality": "one"}, testRand(), testutils.TestLogger())
+	seen := make(map[any]bool)
+	for range 10 {
+		val, err := g.EvaluateAny()
+		if err != nil || seen[val] {
+			t.Fatalf("EvaluateAny() = %v, %v, want unused values", val, err)
+		}
+		seen[val] = true
+	}
+	if _, err := g.EvaluateAny(); !errors.Is(err, ErrRefExhausted) {
+		t.Errorf("EvaluateAny() error = %v, want ErrRefExhausted", err)
+	}
+}
+
+func TestBuilder_Refs(t *testing.T) {
+	structs := refStructs()
+	pool := NewRefPool(structs)
+	builder := NewBuilder(Options{MaxDepth: 5, Refs: pool}, NewRandSource(1), testutils.TestLogger())
+
+	// the first employee has no manager yet
+	g, err := builder.Build(structs["Employee"], nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for i := range 5 {
+		val, _ := g.EvaluateAny()
+		record := val.(*Record)
+		if manager := record.Fields[1].Value; (manager == nil) != (i == 0) {
+			t.Errorf("Employee %d has manager %v", i, manager)
+		}
+		pool.Collect("Employee", record)
+	}
+
+	structs["Order"].Fields[1].MockTags = map[string]string{"ref": "Employee.ID"}
+	structs["Order"].Fields[1].Type = &typeinfo.Type{Kind: typeinfo.String}
+	if _, err := builder.Build(structs["Order"], nil); err == nil || !strings.Contains(err.Error(), "can not reference") {
+		t.Errorf("Build() error = %v, want a type mismatch", err)
+	}
+}
+
+func TestBuilder_RefsReleased(t *testing.T) {
+	intType := &typeinfo.Type{Kind: typeinfo.Int}
+	// four profiles take all four users, so a user chosen for a discarded profile has to be chosen again
+	profile := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Profile", Fields: []typeinfo.Field{
+		{Name: "UserID", Type: intType, MockTags: map[string]string{"ref": "User.ID", "cardinality": "one"}},
+		{Name: "Parity", Type: intType, MockTags: map[string]string{"expr": "UserID%2", "unique": "slot"}},
+		{Name: "Slot", Type: intType, MockTags: map[string]string{"min": "1", "max": "2", "unique": "slot"}},
+	}}
+	structs := refStructs()
+	structs["Profile"] = profile
+
+	for seed := range int64(10) {
+		pool := NewRefPool(structs)
+		for i := range 4 {
+			pool.Collect("User", &Record{Fields: []RecordField{{Field: typeinfo.Field{Name: "ID"}, Value: i + 1}}})
+		}
+		g, err := NewBuilder(Options{MaxDepth: 5, Refs: pool}, NewRandSource(seed), testutils.TestLogger()).Build(profile, nil)
+		if err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		users := make(map[any]bool)
+		for range 4 {
+			val, err := g.EvaluateAny()
+			if err != nil {
+				t.Fatalf("seed %d: EvaluateAny() error = %v", seed, err)
+			}
+			record := val.(*Record)
+			pool.Collect("Profile", record)
+			if user := record.Fields[0].Value; users[user] {
+				t.Fatalf("seed %d: user %v is chosen twice", seed, user)
+			} else {
+				users[user] = true
+			}
+		}
+	}
+
+	// values not satisfying the comparison stay free
+	pool := NewRefPool(structs)
+	for i := range 10 {
+		pool.Collect("User", &Record{Fields: []RecordField{{Field: typeinfo.Field{Name: "ID"}, Value: i}}})
+	}
+	ref := NewRefGenerator(pool, map[string]string{"ref": "User.ID", "cardinality": "one"}, testRand(), testutils.TestLogger())
+	g := NewConstraintGenerator(ref, map[string]string{"gte": "Floor"}, pool, testRand(), testutils.TestLogger()).(RecordGenerator)
+	record := &Record{Fields: []RecordField{{Field: typeinfo.Field{Name: "Floor"}, Value: 8}}}
+	for range 2 {
+		if val, err := g.EvaluateRecord(record); err != nil || val.(int) < 8 {
+			t.Fatalf("EvaluateRecord() = %v, %v, want a value from 8", val, err)
+		}
+	}
+	if free := len(ref.(*RefGenerator).free); free != 8 {
+		t.Errorf("%d values are free after choosing 2 of 10, want 8", free)
+	}
+}
+
+func TestNewRefGenerator_Invalid(t *testing.T) {
+	for _, tags := range []map[string]string{
+		{"ref": "User.ID", "cardinality": "two"},
+		{"ref": "User.ID", "distribution": "normal"},
+		{"ref": "User.ID", "cardinality": "one", "distribution": "zipf"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewRefGenerator(%v) did not panic", tags)
+				}
+			}()
+			NewRefGenerator(NewRefPool(nil), tags, testRand(), testutils.TestLogger())
+		}()
+	}
+}
@@ -0,0 +1,664 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// maxConstraintAttempts limits the number of values generated for a field until its constraints are satisfied.
+const maxConstraintAttempts = 100
+
+var (
+	ErrConstraint   = errors.New("can not satisfy field constraint")
+	ErrFieldCycle   = errors.New("cyclic field references")
+	ErrUnknownField = errors.New("unknown field referenced")
+)
+
+// RelationTags are mock tags relating a field to other fields of the same struct.
+// Comparisons hold a field name, e.g. "after=CreatedAt"; "expr" and "template" derive the value from other fields.
+// They do not configure the generated values, so fields tagged only with them still get heuristic tags.
+var RelationTags = []string{"after", "before", "gt", "gte", "lt", "lte", "expr", "template"}
+
+// RecordGenerator generates a field value from other fields of the same record.
+// StructGenerator evaluates fields in an order where the referenced fields are generated first.
+type RecordGenerator interface {
+	AnyGenerator
+	// References returns the names of the fields the value depends on.
+	References() []string
+	// EvaluateRecord returns a value for the partially generated record.
+	EvaluateRecord(record *Record) (any, error)
+}
+
+// boundedGenerator is implemented by generators of ordered values whose range can be narrowed.
+// ConstraintGenerator passes the referenced values as bounds instead of redrawing values outside of them.
+type boundedGenerator interface {
+	// evaluateWithin returns a value within the range of the generator and the inclusive bounds.
+	// Nil bounds and bounds of other types are ignored. ok is false if no value is within the bounds.
+	evaluateWithin(low, high any) (value any, ok bool, err error)
+}
+
+// boundedFunc is a function implementing boundedGenerator.
+type boundedFunc func(low, high any) (any, bool, error)
+
+func (f boundedFunc) evaluateWithin(low, high any) (any, bool, error) {
+	return f(low, high)
+}
+
+// asBounded returns the generator evaluating values of gen within bounds, or nil if its range can not be narrowed.
+func asBounded(gen AnyGenerator) boundedGenerator {
+	if wrapper, ok := gen.(interface{ bounded() boundedGenerator }); ok {
+		return wrapper.bounded()
+	}
+	return nil
+}
+
+// narrowRange narrows the range of numbers from min to max to the inclusive bounds.
+// Ranges of integers are narrowed to the integers within the bounds. ok is false if the range becomes empty.
+func narrowRange(min, max constant.Value, low, high any) (constant.Value, constant.Value, bool) {
+	integer := min.Kind() == constant.Int
+	if bound, err := toConstant(low); err == nil {
+		if integer {
+			bound = roundConstant(bound, math.Ceil)
+		}
+		if constant.Compare(bound, token.GTR, min) {
+			min = bound
+		}
+	}
+	if bound, err := toConstant(high); err == nil {
+		if integer {
+			bound = roundConstant(bound, math.Floor)
+		}
+		if constant.Compare(bound, token.LSS, max) {
+			max = bound
+		}
+	}
+	return min, max, !constant.Compare(min, token.GTR, max)
+}
+
+// roundConstant rounds a constant to an integer with math.Ceil or math.Floor.
+func roundConstant(value constant.Value, round func(float64) float64) constant.Value {
+	if value.Kind() == constant.Int {
+		return value
+	}
+	f, _ := constant.Float64Val(value)
+	return constant.ToInt(constant.MakeFloat64(round(f)))
+}
+
+// splitRelationTags separates the relation tags of a field from the tags of its type.
+func splitRelationTags(tags map[string]string) (own, relations map[string]string) {
+	for key, value := range tags {
+		if slices.Contains(RelationTags, key) {
+			if relations == nil {
+				relations = make(map[string]string)
+			}
+			relations[key] = value
+			continue
+		}
+		if own == nil {
+			own = make(map[string]string, len(tags))
+		}
+		own[key] = value
+	}
+	return own, relations
+}
+
+// constraint is a comparison of a field with another field, like "after=CreatedAt".
+type constraint struct {
+	op  string // gt, gte, lt or lte
+	ref string // path of the referenced field, e.g. "CreatedAt" or "Period.Start"
+}
+
+// ConstraintGenerator generates values of a field satisfying comparisons with other fields.
+// Values of times, durations and numbers are generated within the referenced values,
+// values of other kinds are generated until they satisfy the comparisons.
+// Nil values, like nil pointers, and nil referenced fields satisfy every comparison.
+type ConstraintGenerator struct {
+	elem        AnyGenerator
+	bounded     boundedGenerator // elem narrowed to the referenced values, nil if its range can not be narrowed
+	constraints []constraint
+	refs        *RefPool // pool of one-to-one references released by rejected values, may be nil
+	BaseGenerator
+}
+
+// NewConstraintGenerator creates a ConstraintGenerator using "after", "before", "gt", "gte", "lt" and "lte" tags,
+// each holding the name of a field of the same struct. "after" is the same as "gt" and "before" as "lt".
+// One-to-one references chosen for rejected values are returned to refs.
+func NewConstraintGenerator(elem AnyGenerator, tags map[string]string, refs *RefPool, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	ops := map[string]string{"after": "gt", "before": "lt", "gt": "gt", "gte": "gte", "lt": "lt", "lte": "lte"}
+	g := &ConstraintGenerator{elem: elem, bounded: asBounded(elem), refs: refs, BaseGenerator: BaseGenerator{rand, logger}}
+	for _, tag := range RelationTags {
+		if op, ok := ops[tag]; ok && tags[tag] != "" {
+			g.constraints = append(g.constraints, constraint{op, tags[tag]})
+		}
+	}
+	logger.Debug("ConstraintGenerator created", "constraints", g.constraints, "bounded", g.bounded != nil)
+	return g
+}
+
+// References returns the compared fields and the references of the element generator.
+func (g *ConstraintGenerator) References() []string {
+	var refs []string
+	for _, c := range g.constraints {
+		refs = append(refs, c.ref)
+	}
+	if elem, ok := g.elem.(RecordGenerator); ok {
+		refs = append(refs, elem.References()...)
+	}
+	return refs
+}
+
+// EvaluateAny can not compare values without a record, it returns an unconstrained value.
+func (g *ConstraintGenerator) EvaluateAny() (any, error) {
+	return g.elem.EvaluateAny()
+}
+
+// EvaluateRecord returns a value satisfying all comparisons with the fields of the record.
+func (g *ConstraintGenerator) EvaluateRecord(record *Record) (any, error) {
+	var low, high any
+	if g.bounded != nil {
+		var err error
+		if low, high, err = g.bounds(record); err != nil {
+			return nil, err
+		}
+	}
+
+	for range maxConstraintAttempts {
+		mark := g.refs.mark()
+		var value any
+		var err error
+		if elem, ok := g.elem.(RecordGenerator); ok {
+			value, err = elem.EvaluateRecord(record)
+		} else if g.bounded != nil {
+			var ok bool
+			value, ok, err = g.bounded.evaluateWithin(low, high)
+			if err == nil && !ok {
+				g.logger.Error("No value within field constraints", "constraints", g.constraints, "low", low, "high", high)
+				return nil, fmt.Errorf("%w: %s, no value between %v and %v", ErrConstraint, g.describe(), low, high)
+			}
+		} else {
+			value, err = g.elem.EvaluateAny()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ok, err := g.satisfies(value, record)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			g.logger.Debug("Evaluate generated value", "value", value)
+			return value, nil
+		}
+		release(g.refs.since(mark))
+	}
+	g.logger.Error("Failed to satisfy field constraints", "constraints", g.constraints, "attempts", maxConstraintAttempts)
+	return nil, fmt.Errorf("%w: %s after %d attempts", ErrConstraint, g.describe(), maxConstraintAttempts)
+}
+
+// bounds returns the greatest referenced value of "gt" and "gte" comparisons
+// and the least referenced value of "lt" and "lte" comparisons, nil if there are none.
+func (g *ConstraintGenerator) bounds(record *Record) (low, high any, err error) {
+	for _, c := range g.constraints {
+		ref, ok := record.Lookup(c.ref)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnknownField, c.ref)
+		}
+		if ref == nil {
+			continue
+		}
+		bound, sign := &low, 1
+		if c.op == "lt" || c.op == "lte" {
+			bound, sign = &high, -1
+		}
+		if *bound == nil {
+			*bound = ref
+			continue
+		}
+		cmp, err := compareValues(ref, *bound)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s=%s: %w", c.op, c.ref, err)
+		}
+		if cmp*sign > 0 {
+			*bound = ref
+		}
+	}
+	return low, high, nil
+}
+
+// satisfies reports whether the value satisfies all comparisons.
+func (g *ConstraintGenerator) satisfies(value any, record *Record) (bool, error) {
+	for _, c := range g.constraints {
+		ref, ok := record.Lookup(c.ref)
+		if !ok {
+			return false, fmt.Errorf("%w: %s", ErrUnknownField, c.ref)
+		}
+		if value == nil || ref == nil {
+			continue
+		}
+		cmp, err := compareValues(value, ref)
+		if err != nil {
+			return false, fmt.Errorf("%s=%s: %w", c.op, c.ref, err)
+		}
+		if !(c.op == "gt" && cmp > 0 || c.op == "gte" && cmp >= 0 || c.op == "lt" && cmp < 0 || c.op == "lte" && cmp <= 0) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// describe returns the constraints as tags, e.g. "gt=CreatedAt;lte=ExpiresAt".
+func (g *ConstraintGenerator) describe() string {
+	parts := make([]string, len(g.constraints))
+	for i, c := range g.constraints {
+		parts[i] = c.op + "=" + c.ref
+	}
+	return strings.Join(parts, ";")
+}
+
+// compareValues compares two generated values of numeric kinds, strings, times or durations.
+// It returns -1, 0 or 1 like cmp.Compare.
+func compareValues(a, b any) (int, error) {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Compare(tb), nil
+		}
+		return 0, fmt.Errorf("can not compare %T with %T", a, b)
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() == reflect.String && vb.Kind() == reflect.String {
+		return strings.Compare(va.String(), vb.String()), nil
+	}
+	ca, errA := toConstant(a)
+	cb, errB := toConstant(b)
+	if errA != nil || errB != nil {
+		return 0, fmt.Errorf("can not compare %T with %T", a, b)
+	}
+	switch {
+	case constant.Compare(ca, token.LSS, cb):
+		return -1, nil
+	case constant.Compare(ca, token.GTR, cb):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// toConstant converts a generated integer or float, including named types like time.Duration, to an exact constant.
+func toConstant(value any) (constant.Value, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return constant.MakeInt64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return constant.MakeUint64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(v.Float()) || math.IsInf(v.Float(), 0) {
+			return nil, fmt.Errorf("non-finite number %v", value)
+		}
+		return constant.MakeFloat64(v.Float()), nil
+	}
+	return nil, fmt.Errorf("%T is not a number", value)
+}
+
+// ExprGenerator derives a number from other fields of the record with an arithmetic expression,
+// like "Price*Quantity".
+type ExprGenerator struct {
+	expr ast.Expr
+	kind typeinfo.Kind
+	refs []string
+	BaseGenerator
+}
+
+// NewExprGenerator creates an ExprGenerator using the "expr" tag for a field of a numeric kind.
+// Expressions use Go syntax with +, -, *, / and %, parentheses, numeric literals
+// and field names, e.g. "(Price-Discount)*Quantity" or "Item.Price*1.2".
+// Integer division truncates like in Go. It panics on invalid expressions and unsupported kinds.
+func NewExprGenerator(tag string, kind typeinfo.Kind, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	if kind < typeinfo.Int || kind > typeinfo.Float64 {
+		logger.Error("Expr tag is not supported for kind", "kind", kind)
+		panic(fmt.Sprintf("expr tag is not supported for %s", kind))
+	}
+	expr, err := parser.ParseExpr(tag)
+	if err != nil {
+		logger.Error("Failed to parse expr tag", "expr", tag, "error", err)
+		panic(err)
+	}
+	refs, err := exprReferences(expr)
+	if err != nil {
+		logger.Error("Invalid expr tag", "expr", tag, "error", err)
+		panic(err)
+	}
+
+	logger.Debug("ExprGenerator created", "expr", tag, "kind", kind, "references", refs)
+	return &ExprGenerator{expr, kind, refs, BaseGenerator{rand, logger}}
+}
+
+// exprReferences returns the field paths used in an expression and rejects unsupported syntax.
+func exprReferences(expr ast.Expr) ([]string, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return nil, fmt.Errorf("unsupported literal %s", e.Value)
+		}
+		return nil, nil
+	case *ast.Ident, *ast.SelectorExpr:
+		path, ok := selectorPath(e)
+		if !ok {
+			return nil, fmt.Errorf("unsupported field reference")
+		}
+		return []string{path}, nil
+	case *ast.ParenExpr:
+		return exprReferences(e.X)
+	case *ast.UnaryExpr:
+		if e.Op != token.ADD && e.Op != token.SUB {
+			return nil, fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		return exprReferences(e.X)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO, token.REM:
+		default:
+			return nil, fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		left, err := exprReferences(e.X)
+		if err != nil {
+			return nil, err
+		}
+		right, err := exprReferences(e.Y)
+		if err != nil {
+			return nil, err
+		}
+		return append(left, right...), nil
+	}
+	return nil, fmt.Errorf("unsupported expression %T", expr)
+}
+
+// selectorPath returns the dotted path of an identifier or a selector, like "Item.Price".
+func selectorPath(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name, true
+	case *ast.SelectorExpr:
+		if path, ok := selectorPath(e.X); ok {
+			return path + "." + e.Sel.Name, true
+		}
+	}
+	return "", false
+}
+
+// References returns the fields used in the expression.
+func (g *ExprGenerator) References() []string {
+	return g.refs
+}
+
+// EvaluateAny can not evaluate an expression without a record.
+func (g *ExprGenerator) EvaluateAny() (any, error) {
+	return nil, fmt.Errorf("expr needs the fields of a record")
+}
+
+// EvaluateRecord evaluates the expression with the fields of the record.
+// The result is nil if a referenced field is nil, like a nil pointer.
+func (g *ExprGenerator) EvaluateRecord(record *Record) (any, error) {
+	result, err := g.eval(g.expr, record)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	if g.kind < typeinfo.Float32 && result.Kind() == constant.Float {
+		// truncated like a conversion of a float to an integer type
+		f, _ := constant.Float64Val(result)
+		result = constant.MakeFloat64(math.Trunc(f))
+	}
+
+	var value any
+	if g.kind >= typeinfo.Float32 {
+		f, _ := constant.Float64Val(result)
+		value, err = parseKindValue(g.kind, strconv.FormatFloat(f, 'g', -1, 64))
+	} else {
+		value, err = parseKindValue(g.kind, constant.ToInt(result).ExactString())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expr result %s: %w", result, err)
+	}
+	g.logger.Debug("Evaluate generated value", "value", value)
+	return value, nil
+}
+
+// eval evaluates an expression validated by exprReferences. It returns nil if a referenced field is nil.
+func (g *ExprGenerator) eval(expr ast.Expr, record *Record) (constant.Value, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(e.Value, e.Kind, 0), nil
+	case *ast.Ident, *ast.SelectorExpr:
+		path, _ := selectorPath(e)
+		value, ok := record.Lookup(path)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownField, path)
+		}
+		if value == nil {
+			return nil, nil
+		}
+		result, err := toConstant(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", path, err)
+		}
+		return result, nil
+	case *ast.ParenExpr:
+		return g.eval(e.X, record)
+	case *ast.UnaryExpr:
+		x, err := g.eval(e.X, record)
+		if err != nil || x == nil {
+			return nil, err
+		}
+		return constant.UnaryOp(e.Op, x, 0), nil
+	case *ast.BinaryExpr:
+		x, err := g.eval(e.X, record)
+		if err != nil || x == nil {
+			return nil, err
+		}
+		y, err := g.eval(e.Y, record)
+		if err != nil || y == nil {
+			return nil, err
+		}
+		op := e.Op
+		if op == token.QUO || op == token.REM {
+			if constant.Sign(y) == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				if op == token.QUO {
+					op = token.QUO_ASSIGN // integer division
+				}
+			} else if op == token.REM {
+				return nil, fmt.Errorf("operator %% needs integers")
+			}
+		}
+		return constant.BinaryOp(x, op, y), nil
+	}
+	return nil, fmt.Errorf("unsupported expression %T", expr)
+}
+
+// TemplateGenerator derives a value from other fields of the record with a text/template,
+// like "{{.FirstName}} {{.LastName}}".
+type TemplateGenerator struct {
+	template *template.Template
+	kind     typeinfo.Kind
+	refs     []string
+	BaseGenerator
+}
+
+// NewTemplateGenerator creates a TemplateGenerator using the "template" tag for a field of a basic kind.
+// The template is executed with the fields of the record, nested structs are accessed like "{{.Address.City}}".
+// Results for fields of other kinds than string are parsed, e.g. "{{.Year}}0" for an int.
+// It panics on invalid templates.
+func NewTemplateGenerator(tag string, kind typeinfo.Kind, rand *rand.Rand, logger *slog.Logger) AnyGenerator {
+	if !kind.IsBasic() {
+		logger.Error("Template tag is not supported for kind", "kind", kind)
+		panic(fmt.Sprintf("template tag is not supported for %s", kind))
+	}
+	tmpl, err := template.New("template").Option("missingkey=error").Parse(tag)
+	if err != nil {
+		logger.Error("Failed to parse template tag", "template", tag, "error", err)
+		panic(err)
+	}
+	var refs []string
+	if tmpl.Tree != nil {
+		refs = templateReferences(tmpl.Tree.Root, refs)
+	}
+
+	logger.Debug("TemplateGenerator created", "template", tag, "kind", kind, "references", refs)
+	return &TemplateGenerator{tmpl, kind, refs, BaseGenerator{rand, logger}}
+}
+
+// templateReferences appends the first names of field chains used in a template, like "Address" of ".Address.City".
+func templateReferences(node parse.Node, refs []string) []string {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return refs
+		}
+		for _, child := range n.Nodes {
+			refs = templateReferences(child, refs)
+		}
+	case *parse.ActionNode:
+		refs = templateReferences(n.Pipe, refs)
+	case *parse.PipeNode:
+		if n == nil {
+			return refs
+		}
+		for _, cmd := range n.Cmds {
+			refs = templateReferences(cmd, refs)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			refs = templateReferences(arg, refs)
+		}
+	case *parse.FieldNode:
+		refs = append(refs, n.Ident[0])
+	case *parse.IfNode:
+		refs = templateBranchReferences(&n.BranchNode, refs)
+	case *parse.RangeNode:
+		refs = templateBranchReferences(&n.BranchNode, refs)
+	case *parse.WithNode:
+		refs = templateBranchReferences(&n.BranchNode, refs)
+	case *parse.TemplateNode:
+		refs = templateReferences(n.Pipe, refs)
+	}
+	return refs
+}
+
+func templateBranchReferences(n *parse.BranchNode, refs []string) []string {
+	refs = templateReferences(n.Pipe, refs)
+	refs = templateReferences(n.List, refs)
+	return templateReferences(n.ElseList, refs)
+}
+
+// References returns the fields used in the template.
+// Names that are not fields, like fields of range elements, are ignored when ordering fields.
+func (g *TemplateGenerator) References() []string {
+	return g.refs
+}
+
+// EvaluateAny can not execute a template without a record.
+func (g *TemplateGenerator) EvaluateAny() (any, error) {
+	return nil, fmt.Errorf("template needs the fields of a record")
+}
+
+// EvaluateRecord executes the template with the fields of the record.
+func (g *TemplateGenerator) EvaluateRecord(record *Record) (any, error) {
+	var result strings.Builder
+	if err := g.template.Execute(&result, record.Map()); err != nil {
+		return nil, err
+	}
+	value, err := parseKindValue(g.kind, result.String())
+	if err != nil {
+		return nil, fmt.Errorf("template result %q: %w", result.String(), err)
+	}
+	g.logger.Debug("Evaluate generated value", "value", value)
+	return value, nil
+}
+
+// fieldDeps returns the indexes of the fields each field depends on, like "CreatedAt" for "after=CreatedAt".
+// It fails on unknown fields in constraints and expressions.
+func fieldDeps(fields []FieldGenerator) ([][]int, error) {
+	index := make(map[string]int, len(fields))
+	for i, f := range fields {
+		index[f.Field.Name] = i
+	}
+
+	deps := make([][]int, len(fields))
+	for i, f := range fields {
+		gen, ok := f.Generator.(RecordGenerator)
+		if !ok {
+			continue
+		}
+		_, isTemplate := gen.(*TemplateGenerator)
+		for _, ref := range gen.References() {
+			name, _, _ := strings.Cut(ref, ".")
+			j, ok := index[name]
+			if !ok {
+				if isTemplate {
+					continue // e.g. fields of range elements
+				}
+				return nil, fmt.Errorf("field %s: %w: %s", f.Field.Name, ErrUnknownField, ref)
+			}
+			deps[i] = append(deps[i], j)
+		}
+	}
+	return deps, nil
+}
+
+// fieldOrder returns the indexes of the fields in an order where fields referenced by RecordGenerators,
+// like "CreatedAt" of "after=CreatedAt", are evaluated before the fields referencing them.
+// Fields keep their declaration order otherwise. It fails on cycles.
+func fieldOrder(fields []FieldGenerator, deps [][]int) ([]int, error) {
+	order := make([]int, 0, len(fields))
+	state := make([]int, len(fields)) // 0 - not visited, 1 - visiting, 2 - done
+	var stack []int
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case 1:
+			start := slices.Index(stack, i)
+			names := make([]string, 0, len(stack)-start+1)
+			for _, j := range append(stack[start:], i) {
+				names = append(names, fields[j].Field.Name)
+			}
+			return fmt.Errorf("%w: %s", ErrFieldCycle, strings.Join(names, " -> "))
+		case 2:
+			return nil
+		}
+		state[i] = 1
+		stack = append(stack, i)
+		for _, j := range deps[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = 2
+		order = append(order, i)
+		return nil
+	}
+	for i := range fields {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func buildRecord(t *testing.T, typ *typeinfo.Type) *Record {
+	t.Helper()
+	g, err := testBuilder(5).Build(typ, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	val, err := g.EvaluateAny()
+	if err != nil {
+		t.Fatalf("EvaluateAny() error = %v", err)
+	}
+	return val.(*Record)
+}
+
+func TestBuilder_Constraints(t *testing.T) {
+	timeType := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "time", Name: "Time"}
+	intType := &typeinfo.Type{Kind: typeinfo.Int}
+	event := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Event", Fields: []typeinfo.Field{
+		// declared before the field it depends on
+		{Name: "UpdatedAt", Type: timeType, MockTags: map[string]string{"range": "past", "after": "CreatedAt"}},
+		{Name: "CreatedAt", Type: timeType, MockTags: map[string]string{"range": "past"}},
+		{Name: "Min", Type: intType, MockTags: map[string]string{"min": "0", "max": "50"}},
+		{Name: "Max", Type: intType, MockTags: map[string]string{"min": "0", "max": "50", "gte": "Min"}},
+		{Name: "DeletedAt", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: timeType}, MockTags: map[string]string{"range": "future", "nullable": "0.5", "after": "UpdatedAt"}},
+	}}
+
+	for range 100 {
+		record := buildRecord(t, event)
+		updated := record.Fields[0].Value.(time.Time)
+		created := record.Fields[1].Value.(time.Time)
+		if !updated.After(created) {
+			t.Fatalf("UpdatedAt %v is not after CreatedAt %v", updated, created)
+		}
+		if lo, hi := record.Fields[2].Value.(int), record.Fields[3].Value.(int); hi < lo {
+			t.Fatalf("Max %d is less than Min %d", hi, lo)
+		}
+		if deleted, ok := record.Fields[4].Value.(time.Time); ok && !deleted.After(updated) {
+			t.Fatalf("DeletedAt %v is not after UpdatedAt %v", deleted, updated)
+		}
+	}
+}
+
+func TestBuilder_ConstraintsLargeCount(t *testing.T) {
+	// the referenced values bound the ranges, instead of redrawing values outside of them
+	timeType := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "time", Name: "Time"}
+	durationType := &typeinfo.Type{Kind: typeinfo.Int64, PkgPath: "time", Name: "Duration"}
+	uintType := &typeinfo.Type{Kind: typeinfo.Uint16}
+	floatType := &typeinfo.Type{Kind: typeinfo.Float64}
+	booking := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Booking", Fields: []typeinfo.Field{
+		{Name: "StartDate", Type: timeType, MockTags: map[string]string{"range": "future"}},
+		{Name: "EndDate", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: timeType}, MockTags: map[string]string{"range": "future", "after": "StartDate"}},
+		{Name: "Timeout", Type: durationType, MockTags: map[string]string{"max": "1h"}},
+		{Name: "Retry", Type: durationType, MockTags: map[string]string{"max": "1h", "gt": "Timeout"}},
+		{Name: "Seats", Type: uintType},
+		{Name: "Taken", Type: uintType, MockTags: map[string]string{"gte": "Seats"}},
+		{Name: "Price", Type: floatType, MockTags: map[string]string{"min": "0", "max": "100"}},
+		{Name: "Discount", Type: floatType, MockTags: map[string]string{"min": "0", "max": "100", "lt": "Price"}},
+	}}
+
+	g, err := testBuilder(5).Build(booking, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	for range 1000 {
+		value, err := g.EvaluateAny()
+		if err != nil {
+			t.Fatalf("EvaluateAny() error = %v", err)
+		}
+		record := value.(*Record)
+		start := record.Fields[0].Value.(time.Time)
+		if end := record.Fields[1].Value.(time.Time); !end.After(start) {
+			t.Fatalf("EndDate %v is not after StartDate %v", end, start)
+		}
+		if timeout, retry := record.Fields[2].Value.(time.Duration), record.Fields[3].Value.(time.Duration); retry <= timeout {
+			t.Fatalf("Retry %v is not greater than Timeout %v", retry, timeout)
+		}
+		if seats, taken := record.Fields[4].Value.(uint16), record.Fields[5].Value.(uint16); taken < seats {
+			t.Fatalf("Taken %d is less than Seats %d", taken, seats)
+		}
+		if price, discount := record.Fields[6].Value.(float64), record.Fields[7].Value.(float64); discount >= price {
+			t.Fatalf("Discount %v is not less than Price %v", discount, price)
+		}
+	}
+}
+
+func TestBuilder_Derived(t *testing.T) {
+	item := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Item", Fields: []typeinfo.Field{
+		{Name: "Price", Type: &typeinfo.Type{Kind: typeinfo.Float64}, MockTags: map[string]string{"min": "1", "max": "10"}},
+	}}
+	order := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Order", Fields: []typeinfo.Field{
+		{Name: "Total", Type: &typeinfo.Type{Kind: typeinfo.Float64}, MockTags: map[string]string{"expr": "Item.Price*Quantity"}},
+		{Name: "Boxes", Type: &typeinfo.Type{Kind: typeinfo.Int}, MockTags: map[string]string{"expr": "(Quantity+2)/3"}},
+		{Name: "Item", Type: item},
+		{Name: "Quantity", Type: &typeinfo.Type{Kind: typeinfo.Int}, MockTags: map[string]string{"min": "1", "max": "20"}},
+		{Name: "Label", Type: &typeinfo.Type{Kind: typeinfo.String}, MockTags: map[string]string{"template": "{{.Quantity}} x {{printf \"%.2f\" .Item.Price}}"}},
+		{Name: "Count", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: &typeinfo.Type{Kind: typeinfo.Uint8}}, MockTags: map[string]string{"template": "{{.Quantity}}"}},
+	}}
+
+	for range 20 {
+		record := buildRecord(t, order)
+		price := record.Fields[2].Value.(*Record).Fields[0].Value.(float64)
+		quantity := record.Fields[3].Value.(int)
+		if total := record.Fields[0].Value.(float64); total != price*float64(quantity) {
+			t.Errorf("Total = %v, want %v", total, price*float64(quantity))
+		}
+		if boxes := record.Fields[1].Value.(int); boxes != (quantity+2)/3 {
+			t.Errorf("Boxes = %d, want %d", boxes, (quantity+2)/3)
+		}
+		if label, want := record.Fields[4].Value.(string), fmt.Sprintf("%d x %.2f", quantity, price); label != want {
+			t.Errorf("Label = %q, want %q", label, want)
+		}
+		if count := record.Fields[5].Value.(uint8); int(count) != quantity {
+			t.Errorf("Count = %d, want %d", count, quantity)
+		}
+	}
+}
+
+func TestBuilder_RelationErrors(t *testing.T) {
+	intType := &typeinfo.Type{Kind: typeinfo.Int}
+	tests := []struct {
+		name   string
+		fields []typeinfo.Field
+		want   error
+		substr string
+	}{
+		{
+			name: "cycle",
+			fields: []typeinfo.Field{
+				{Name: "A", Type: intType, MockTags: map[string]string{"expr": "B+1"}},
+				{Name: "B", Type: intType, MockTags: map[string]string{"gt": "C"}},
+				{Name: "C", Type: intType, MockTags: map[string]string{"lt": "A"}},
+			},
+			want:   ErrFieldCycle,
+			substr: "A -> B -> C -> A",
+		},
+		{
+			name:   "self reference",
+			fields: []typeinfo.Field{{Name: "A", Type: intType, MockTags: map[string]string{"gt": "A"}}},
+			want:   ErrFieldCycle,
+			substr: "A -> A",
+		},
+		{
+			name:   "unknown field",
+			fields: []typeinfo.Field{{Name: "A", Type: intType, MockTags: map[string]string{"expr": "Price*2"}}},
+			want:   ErrUnknownField,
+			substr: "Price",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testBuilder(5).Build(&typeinfo.Type{Kind: typeinfo.Struct, Name: "T", Fields: tt.fields}, nil)
+			if !errors.Is(err, tt.want) || !strings.Contains(err.Error(), tt.substr) {
+				t.Errorf("Build() error = %v, want %v with %q", err, tt.want, tt.substr)
+			}
+		})
+	}
+}
+
+func TestConstraintGenerator_Unsatisfiable(t *testing.T) {
+	intType := &typeinfo.Type{Kind: typeinfo.Int}
+	typ := &typeinfo.Type{Kind: typeinfo.Struct, Name: "T", Fields: []typeinfo.Field{
+		{Name: "Min", Type: intType, MockTags: map[string]string{"min": "10", "max": "20"}},
+		{Name: "Max", Type: intType, MockTags: map[string]string{"min": "0", "max": "5", "gt": "Min"}},
+	}}
+	g, err := testBuilder(5).Build(typ, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if _, err := g.EvaluateAny(); !errors.Is(err, ErrConstraint) || !strings.Contains(err.Error(), "gt=Min") {
+		t.Errorf("EvaluateAny() error = %v, want ErrConstraint", err)
+	}
+}
+
+func TestExprGenerator(t *testing.T) {
+	record := &Record{Fields: []RecordField{
+		{Field: typeinfo.Field{Name: "A"}, Value: 7},
+		{Field: typeinfo.Field{Name: "B"}, Value: uint8(2)},
+		{Field: typeinfo.Field{Name: "D"}, Value: 90 * time.Minute},
+		{Field: typeinfo.Field{Name: "Zero"}, Value: 0},
+		{Field: typeinfo.Field{Name: "Nil"}, Value: nil},
+	}}
+	tests := []struct {
+		expr    string
+		kind    typeinfo.Kind
+		want    any
+		wantErr bool
+	}{
+		{expr: "A/B", kind: typeinfo.Int, want: 3},
+		{expr: "A%B + -1", kind: typeinfo.Int64, want: int64(0)},
+		{expr: "A/2.0", kind: typeinfo.Float32, want: float32(3.5)},
+		{expr: "A*1.5", kind: typeinfo.Int, want: 10},
+		{expr: "D/60000000000", kind: typeinfo.Uint16, want: uint16(90)},
+		{expr: "A-10", kind: typeinfo.Uint, wantErr: true},
+		{expr: "A/Zero", kind: typeinfo.Int, wantErr: true},
+		{expr: "Nil*2", kind: typeinfo.Int, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			g := NewExprGenerator(tt.expr, tt.kind, testRand(), testutils.TestLogger()).(RecordGenerator)
+			got, err := g.EvaluateRecord(record)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateRecord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("EvaluateRecord() = %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestExprGenerator_Invalid(t *testing.T) {
+	tests := []struct {
+		expr string
+		kind typeinfo.Kind
+	}{
+		{"A+", typeinfo.Int},
+		{"A == B", typeinfo.Int},
+		{"len(A)", typeinfo.Int},
+		{`A + "x"`, typeinfo.Int},
+		{"A+1", typeinfo.String},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewExprGenerator(%q) did not panic", tt.expr)
+				}
+			}()
+			NewExprGenerator(tt.expr, tt.kind, testRand(), testutils.TestLogger())
+		})
+	}
+}
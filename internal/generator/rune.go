@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"unicode/utf8"
+)
+
+// RuneGenerator generates characters of rune values.
+type RuneGenerator struct {
+	chars []rune
+	BaseGenerator
+}
+
+// NewRuneGenerator creates a new RuneGenerator using the "chars" tag,
+// the characters to choose from, e.g. `chars=abc`.
+// Defaults to ASCII letters, digits, "-" and "_" if not provided.
+func NewRuneGenerator(tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[int32] {
+	value, ok := tags["chars"]
+	if !ok {
+		value = chars
+	}
+	if value == "" || !utf8.ValidString(value) {
+		logger.Error("Invalid chars provided", "chars", value)
+		panic(fmt.Sprintf("invalid chars provided: %q", value))
+	}
+
+	logger.Debug("RuneGenerator created", "chars", value)
+	return &RuneGenerator{[]rune(value), BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns one of the configured characters.
+func (g *RuneGenerator) Evaluate() (int32, error) {
+	result := g.chars[g.rand.Intn(len(g.chars))]
+	g.logger.Debug("Evaluate generated value", "value", string(result))
+	return result, nil
+}
+
+func (g *RuneGenerator) Validate() error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+)
+
+func TestRuneGenerator_Chars(t *testing.T) {
+	g := NewRuneGenerator(map[string]string{"chars": "αβγ"}, testRand(), testutils.TestLogger())
+	for range 100 {
+		val, _ := g.Evaluate()
+		if !strings.ContainsRune("αβγ", val) {
+			t.Fatalf("Unexpected rune: %q", val)
+		}
+	}
+}
+
+func TestRuneGenerator_EmptyChars(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for empty chars")
+		}
+	}()
+	NewRuneGenerator(map[string]string{"chars": ""}, testRand(), testutils.TestLogger())
+}
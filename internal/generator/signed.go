@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"go/constant"
 	"log/slog"
 	"math"
 	"math/rand"
@@ -92,6 +93,19 @@ func (g *SignedGenerator[T]) Evaluate() (T, error) {
 	return T(value), nil
 }
 
+// evaluateWithin returns a random signed integer within the range and the bounds.
+func (g *SignedGenerator[T]) evaluateWithin(low, high any) (any, bool, error) {
+	min, max, ok := narrowRange(constant.MakeInt64(g.min), constant.MakeInt64(g.max), low, high)
+	if !ok {
+		return nil, false, nil
+	}
+	narrowed := &SignedGenerator[T]{BaseGenerator: g.BaseGenerator}
+	narrowed.min, _ = constant.Int64Val(min)
+	narrowed.max, _ = constant.Int64Val(max)
+	value, err := narrowed.Evaluate()
+	return value, true, err
+}
+
 func signedClamp(value, min, max int64) int64 {
 	if value < min {
 		return min
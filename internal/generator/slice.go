@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+)
+
+const (
+	defaultMinLen = 1
+	defaultMaxLen = 5
+)
+
+// SliceGenerator generates slices and arrays
+// of values produced by the element generator.
+type SliceGenerator struct {
+	elem   AnyGenerator
+	minLen int
+	maxLen int
+	BaseGenerator
+}
+
+// NewSliceGenerator creates a new SliceGenerator using "minlen" and "maxlen" tags.
+// Defaults to 1 and 5 elements if not provided.
+func NewSliceGenerator(elem AnyGenerator, tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[[]any] {
+	minLen, maxLen := parseLenRange(tags, logger)
+	logger.Debug("SliceGenerator created", "minlen", minLen, "maxlen", maxLen)
+	return &SliceGenerator{elem, minLen, maxLen, BaseGenerator{rand, logger}}
+}
+
+// NewArrayGenerator creates a SliceGenerator that always generates exactly length elements.
+func NewArrayGenerator(elem AnyGenerator, length int, rand *rand.Rand, logger *slog.Logger) Generator[[]any] {
+	logger.Debug("Array SliceGenerator created", "len", length)
+	return &SliceGenerator{elem, length, length, BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns a slice of random length within the configured range.
+func (g *SliceGenerator) Evaluate() ([]any, error) {
+	length := g.minLen + g.rand.Intn(g.maxLen-g.minLen+1)
+	result := make([]any, length)
+	for i := range result {
+		value, err := g.elem.EvaluateAny()
+		if err != nil {
+			g.logger.Error("Failed to evaluate element generator", "index", i, "error", err)
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		result[i] = value
+	}
+	g.logger.Debug("Evaluate generated slice", "len", length)
+	return result, nil
+}
+
+// parseLenRange parses "minlen" and "maxlen" tags.
+// If only one of them is provided, the other one is adjusted to form a valid range.
+func parseLenRange(tags map[string]string, logger *slog.Logger) (int, int) {
+	minLen := parseLenTag(tags, "minlen", defaultMinLen, logger)
+	maxLen := parseLenTag(tags, "maxlen", defaultMaxLen, logger)
+
+	if _, ok := tags["maxlen"]; !ok && minLen > maxLen {
+		maxLen = minLen
+	}
+	if _, ok := tags["minlen"]; !ok && maxLen < minLen {
+		minLen = maxLen
+	}
+	if minLen > maxLen {
+		logger.Error("Invalid length range provided", "minlen", minLen, "maxlen", maxLen)
+		panic(fmt.Sprintf("invalid length range provided: minlen %d is greater than maxlen %d", minLen, maxLen))
+	}
+	return minLen, maxLen
+}
+
+func parseLenTag(tags map[string]string, name string, defaultValue int, logger *slog.Logger) int {
+	value, ok := tags[name]
+	if !ok {
+		return defaultValue
+	}
+	l, err := strconv.Atoi(value)
+	if err != nil {
+		logger.Error("Failed to parse length tag", name, value, "error", err)
+		panic(err)
+	}
+	if l < 0 {
+		logger.Error("Invalid length provided", name, l)
+		panic(fmt.Sprintf("invalid %s provided: %d", name, l))
+	}
+	return l
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestSliceGenerator_Length(t *testing.T) {
+	tags := map[string]string{
+		"minlen": "2",
+		"maxlen": "4",
+	}
+	elem := &GenericGenerator[int]{impl: NewSignedGenerator[int](map[string]string{}, testRand(), testutils.TestLogger())}
+	g := NewSliceGenerator(elem, tags, testRand(), testutils.TestLogger()).(*SliceGenerator)
+
+	for i := 0; i < 100; i++ {
+		val, _ := g.Evaluate()
+		if len(val) < 2 || len(val) > 4 {
+			t.Fatalf("Length %d out of range [2,4]", len(val))
+		}
+	}
+}
+
+func TestSliceGenerator_Defaults(t *testing.T) {
+	g := NewSliceGenerator(&NilGenerator{}, map[string]string{"minlen": "10"}, testRand(), testutils.TestLogger()).(*SliceGenerator)
+	if g.minLen != 10 || g.maxLen != 10 {
+		t.Errorf("Expected maxlen to follow minlen, got min=%d, max=%d", g.minLen, g.maxLen)
+	}
+}
+
+func TestBuilder_SliceElementTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     *typeinfo.Type
+		tags    map[string]string
+		wantLen func(int) bool
+	}{
+		{
+			name:    "slice",
+			typ:     &typeinfo.Type{Kind: typeinfo.Slice, Elem: &typeinfo.Type{Kind: typeinfo.Int}},
+			tags:    map[string]string{"minlen": "3", "maxlen": "3", "elem.min": "1", "elem.max": "10"},
+			wantLen: func(l int) bool { return l == 3 },
+		},
+		{
+			name:    "array",
+			typ:     &typeinfo.Type{Kind: typeinfo.Array, Len: 7, Elem: &typeinfo.Type{Kind: typeinfo.Int}},
+			tags:    map[string]string{"maxlen": "2", "elem.min": "1", "elem.max": "10"},
+			wantLen: func(l int) bool { return l == 7 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := testBuilder(5).Build(tt.typ, tt.tags)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			val, _ := g.EvaluateAny()
+			elems := val.([]any)
+			if !tt.wantLen(len(elems)) {
+				t.Errorf("Unexpected length: %d", len(elems))
+			}
+			for _, elem := range elems {
+				if v := elem.(int); v < 1 || v > 10 {
+					t.Errorf("Element %d out of range [1,10]", v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// Record is a generated struct value.
+// Fields are kept in declaration order.
+type Record struct {
+	Type   *typeinfo.Type
+	Fields []RecordField
+}
+
+// RecordField is a generated value of a single struct field.
+// Nested structs are represented by *Record values.
+type RecordField struct {
+	Field typeinfo.Field
+	Value any
+}
+
+// Lookup returns the value of a field by its name or by a dotted path into nested structs, like "Address.City".
+// The value is nil if a struct on the path is nil.
+func (r *Record) Lookup(path string) (any, bool) {
+	name, rest, nested := strings.Cut(path, ".")
+	for _, f := range r.Fields {
+		if f.Field.Name != name {
+			continue
+		}
+		if !nested {
+			return f.Value, true
+		}
+		record, ok := f.Value.(*Record)
+		if !ok {
+			return nil, f.Value == nil
+		}
+		if record == nil {
+			return nil, true
+		}
+		return record.Lookup(rest)
+	}
+	return nil, false
+}
+
+// Map returns the fields of the record by their names, nested records are converted to maps as well.
+func (r *Record) Map() map[string]any {
+	result := make(map[string]any, len(r.Fields))
+	for _, f := range r.Fields {
+		if record, ok := f.Value.(*Record); ok && record != nil {
+			result[f.Field.Name] = record.Map()
+			continue
+		}
+		result[f.Field.Name] = f.Value
+	}
+	return result
+}
+
+// FieldGenerator is a generator of a single struct field.
+type FieldGenerator struct {
+	Field     typeinfo.Field
+	Generator AnyGenerator
+}
+
+// StructGenerator generates records
+// by evaluating a generator per struct field.
+type StructGenerator struct {
+	typ    *typeinfo.Type
+	fields []FieldGenerator
+	order  []int          // indexes of fields in evaluation order
+	unique []*uniqueGroup // fields whose values are unique across instances
+	refs   *RefPool       // pool of one-to-one references released by regenerated fields, may be nil
+	BaseGenerator
+}
+
+// NewStructGenerator creates a new StructGenerator using prepared field generators.
+// Fields are evaluated in the given order of their indexes, nil means declaration order.
+// Fields of unique groups are regenerated until their values differ from the values of previous instances,
+// one-to-one references chosen for the discarded values are returned to refs.
+func NewStructGenerator(t *typeinfo.Type, fields []FieldGenerator, order []int, unique []*uniqueGroup, refs *RefPool, rand *rand.Rand, logger *slog.Logger) Generator[*Record] {
+	if order == nil {
+		order = make([]int, len(fields))
+		for i := range order {
+			order[i] = i
+		}
+	}
+	logger.Debug("StructGenerator created", "type", t, "fieldCount", len(fields), "order", order)
+	return &StructGenerator{t, fields, order, unique, refs, BaseGenerator{rand, logger}}
+}
+
+// Evaluate generates every field of the struct in evaluation order.
+// Fields of RecordGenerators are evaluated with the fields generated before them.
+func (g *StructGenerator) Evaluate() (*Record, error) {
+	record := &Record{Type: g.typ, Fields: make([]RecordField, len(g.fields))}
+	for i, f := range g.fields {
+		record.Fields[i].Field = f.Field
+	}
+	picks := make([][]*refPick, len(g.fields)) // one-to-one references chosen by field
+	for _, i := range g.order {
+		if err := g.evaluateField(record, i, picks); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.deduplicate(record, picks); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// evaluateField generates the value of the field with the given index
+// and stores the one-to-one references chosen for it in picks.
+func (g *StructGenerator) evaluateField(record *Record, i int, picks [][]*refPick) error {
+	f := g.fields[i]
+	mark := g.refs.mark()
+	var value any
+	var err error
+	if gen, ok := f.Generator.(RecordGenerator); ok {
+		value, err = gen.EvaluateRecord(record)
+	} else {
+		value, err = f.Generator.EvaluateAny()
+	}
+	if err != nil {
+		g.logger.Error("Failed to evaluate generator for field", "fieldName", f.Field.Name, "error", err)
+		return fmt.Errorf("field %s: %w", f.Field.Name, err)
+	}
+	record.Fields[i].Value = value
+	picks[i] = g.refs.since(mark)
+	return nil
+}
+
+// deduplicate regenerates the fields of unique groups whose values are already generated,
+// and fails if no unique values are generated in maxUniqueAttempts, e.g. when the values of an int8 are exhausted.
+// One-to-one references chosen for the regenerated fields are released.
+func (g *StructGenerator) deduplicate(record *Record, picks [][]*refPick) error {
+	attempts := 0
+	for {
+		i := slices.IndexFunc(g.unique, func(group *uniqueGroup) bool { return group.duplicate(record) })
+		if i < 0 {
+			break
+		}
+		group := g.unique[i]
+		if attempts++; attempts > maxUniqueAttempts {
+			g.logger.Error("Failed to generate unique values", "group", group.name, "instances", len(group.seen), "attempts", maxUniqueAttempts)
+			names := make([]string, len(group.fields))
+			for j, field := range group.fields {
+				names[j] = g.fields[field].Field.Name
+			}
+			return fmt.Errorf("%w of %s after %d attempts with %d instances, the values may be exhausted",
+				ErrUniqueExhausted, strings.Join(names, ", "), maxUniqueAttempts, len(group.seen))
+		}
+		for _, field := range group.retry {
+			release(picks[field])
+			if err := g.evaluateField(record, field, picks); err != nil {
+				return err
+			}
+		}
+	}
+	for _, group := range g.unique {
+		group.add(record)
+	}
+	return nil
+}
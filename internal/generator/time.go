@@ -1,51 +1,462 @@
 package generator
 
 import (
+	"errors"
+	"fmt"
+	"go/constant"
 	"log/slog"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
+	_ "time/tzdata" // time zones of the "tz" tag do not depend on the system database
 )
 
+// maxTimeAttempts limits the number of random times checked against "weekdays" and "hours" tags.
+const maxTimeAttempts = 1000
+
+var ErrNoMatchingTime = errors.New("no time in range matches the weekdays and hours")
+
+// TimeLayouts maps names of the "layout" tag to time.Format layouts.
+// Other values of the tag are used as layouts themselves, e.g. "02.01.2006".
+var TimeLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"RFC822":      time.RFC822,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+	"Kitchen":     time.Kitchen,
+}
+
 // TimeGenerator generates time.Time objects
-// Can generate past, future or current time
+// within a range, or the reference time if no range is provided.
 type TimeGenerator struct {
-	isPast   bool
-	isFuture bool
+	now      time.Time
+	from     time.Time // zero if no range is provided
+	to       time.Time
+	location *time.Location
+	truncate string // "day", "hour", "minute", "second" or empty
+	weekdays []bool // allowed weekdays indexed by time.Weekday, nil for any
+	hours    [2]int // allowed hours from hours[0] up to but not including hours[1]
 	BaseGenerator
 }
 
-// NewTimeGenerator creates a TimeGenerator based on the "range" tag.
-// "range" tag can be "past" or "future". Default is current time.
+// NewTimeGenerator creates a TimeGenerator using "range", "from", "to", "now", "tz", "truncate",
+// "weekdays" and "hours" tags.
+//
+// "range" can be "past" or "future", a year before or after the reference time.
+// "from" and "to" are RFC3339 times, dates like "2024-01-31", relative offsets like "-30d" or "+2h",
+// or "now", and override the bounds set by "range". If only one bound is provided, the other one is a year away.
+// "now" is the reference time as RFC3339, the current time by default.
+// "tz" is an IANA time zone like "Europe/Berlin", the zone of the reference time by default.
+// "truncate" can be "day", "hour", "minute" or "second".
+// "weekdays" is a list of days and day ranges, e.g. "mon-fri" or "sat,sun".
+// "hours" is a range of hours, e.g. "9-17" for times from 9:00 to 16:59.
+// Without a range, the reference time is returned. It panics on invalid tags.
 func NewTimeGenerator(tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[time.Time] {
-	past := false
-	future := false
-	timeRange, ok := tags["range"]
-	if ok {
-		if timeRange == "past" {
-			past = true
-		} else if timeRange == "future" {
-			future = true
+	g := &TimeGenerator{now: time.Now(), truncate: tags["truncate"], hours: [2]int{0, 24}, BaseGenerator: BaseGenerator{rand, logger}}
+
+	if value, ok := tags["now"]; ok {
+		now, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			logger.Error("Failed to parse now tag", "now", value, "error", err)
+			panic(err)
+		}
+		g.now = now
+	}
+
+	g.location = g.now.Location()
+	if value, ok := tags["tz"]; ok {
+		location, err := time.LoadLocation(value)
+		if err != nil {
+			logger.Error("Failed to load time zone", "tz", value, "error", err)
+			panic(err)
 		}
+		g.location = location
 	}
-	logger.Debug("TimeGenerator created", "past", past, "future", future)
-	return &TimeGenerator{past, future, BaseGenerator{rand, logger}}
+	g.now = g.now.In(g.location)
+
+	switch tags["range"] {
+	case "past":
+		g.from, g.to = g.now.AddDate(-1, 0, 0), g.now
+	case "future":
+		g.from, g.to = g.now, g.now.AddDate(1, 0, 0)
+	case "":
+	default:
+		logger.Error("Invalid time range provided", "range", tags["range"])
+		panic(fmt.Sprintf("invalid time range provided: %q", tags["range"]))
+	}
+
+	for name, bound := range map[string]*time.Time{"from": &g.from, "to": &g.to} {
+		value, ok := tags[name]
+		if !ok {
+			continue
+		}
+		t, err := parseTimeBound(value, g.now, g.location)
+		if err != nil {
+			logger.Error("Failed to parse time bound", name, value, "error", err)
+			panic(err)
+		}
+		*bound = t
+	}
+	if g.from.IsZero() != g.to.IsZero() {
+		if g.from.IsZero() {
+			g.from = g.to.AddDate(-1, 0, 0)
+		} else {
+			g.to = g.from.AddDate(1, 0, 0)
+		}
+	}
+	if g.to.Before(g.from) {
+		logger.Error("Invalid time range provided", "from", g.from, "to", g.to)
+		panic(fmt.Sprintf("invalid time range provided: from %s is after to %s", g.from, g.to))
+	}
+
+	switch g.truncate {
+	case "", "day", "hour", "minute", "second":
+	default:
+		logger.Error("Invalid truncate provided", "truncate", g.truncate)
+		panic(fmt.Sprintf("invalid truncate provided: %q", g.truncate))
+	}
+
+	if value, ok := tags["weekdays"]; ok {
+		weekdays, err := parseWeekdays(value)
+		if err != nil {
+			logger.Error("Failed to parse weekdays tag", "weekdays", value, "error", err)
+			panic(err)
+		}
+		g.weekdays = weekdays
+	}
+	if value, ok := tags["hours"]; ok {
+		hours, err := parseHours(value)
+		if err != nil {
+			logger.Error("Failed to parse hours tag", "hours", value, "error", err)
+			panic(err)
+		}
+		g.hours = hours
+	}
+	if g.from.IsZero() && (g.weekdays != nil || g.hours != [2]int{0, 24}) {
+		logger.Error("Weekdays and hours tags need a time range")
+		panic("weekdays and hours tags need a time range")
+	}
+
+	logger.Debug("TimeGenerator created", "now", g.now, "from", g.from, "to", g.to, "location", g.location,
+		"truncate", g.truncate, "weekdays", tags["weekdays"], "hours", g.hours)
+	return g
 }
 
-// Evaluate returns a random time based on the range.
-// If range is not provided, it returns the current time.
+// Evaluate returns a random time within the range matching the weekdays and hours.
+// If no range is provided, it returns the reference time.
 func (g *TimeGenerator) Evaluate() (time.Time, error) {
-	if g.isPast {
-		g.logger.Debug("Generating a past time value")
-		return time.Now().Add(-time.Duration(g.rand.Intn(1000000)) * time.Hour), nil
-	} else if g.isFuture {
-		g.logger.Debug("Generating a future time value")
-		return time.Now().Add(time.Duration(g.rand.Intn(1000000)) * time.Hour), nil
+	if g.from.IsZero() {
+		g.logger.Debug("Returning reference time")
+		return truncateTime(g.now, g.truncate), nil
+	}
+
+	span := g.to.Sub(g.from)
+	for range maxTimeAttempts {
+		result := g.from
+		if span > 0 {
+			result = result.Add(time.Duration(g.rand.Int63n(int64(span))))
+		}
+		result = truncateTime(result, g.truncate)
+		if g.matches(result) {
+			g.logger.Debug("Evaluate generated value", "value", result)
+			return result, nil
+		}
+	}
+	g.logger.Error("No matching time found", "from", g.from, "to", g.to, "attempts", maxTimeAttempts)
+	return time.Time{}, fmt.Errorf("%w: from %s to %s", ErrNoMatchingTime, g.from, g.to)
+}
+
+// evaluateWithin returns a random time within the range and the bounds.
+// Without a range, it returns the reference time.
+func (g *TimeGenerator) evaluateWithin(low, high any) (any, bool, error) {
+	if g.from.IsZero() {
+		value, err := g.Evaluate()
+		return value, true, err
+	}
+	narrowed := *g
+	if t, ok := low.(time.Time); ok && t.After(narrowed.from) {
+		narrowed.from = t
+	}
+	if t, ok := high.(time.Time); ok && t.Before(narrowed.to) {
+		narrowed.to = t
+	}
+	if narrowed.to.Before(narrowed.from) {
+		return nil, false, nil
+	}
+	value, err := narrowed.Evaluate()
+	return value, true, err
+}
+
+// matches reports whether a generated time is within the range, weekdays and hours.
+func (g *TimeGenerator) matches(t time.Time) bool {
+	if t.Before(g.from) || t.After(g.to) {
+		return false
+	}
+	if g.weekdays != nil && !g.weekdays[t.Weekday()] {
+		return false
+	}
+	return t.Hour() >= g.hours[0] && t.Hour() < g.hours[1]
+}
+
+// parseTimeBound parses a value of "from" and "to" tags: "now", a relative offset like "-30d" or "+1h30m",
+// an RFC3339 time, or a date and time like "2024-01-31" or "2024-01-31 12:00:00" in the given location.
+func parseTimeBound(value string, now time.Time, location *time.Location) (time.Time, error) {
+	if value == "now" {
+		return now, nil
+	}
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		return addOffset(now, value)
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.In(location), nil
+	}
+	if t, err := time.ParseInLocation(time.DateTime, value, location); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, value, location)
+}
+
+// addOffset adds a signed offset to t. Offsets are Go durations like "-1h30m",
+// or a number of days, weeks, months or years, like "-30d", "+2w", "-6mo" or "+1y".
+func addOffset(t time.Time, offset string) (time.Time, error) {
+	if d, err := time.ParseDuration(offset); err == nil {
+		return t.Add(d), nil
+	}
+	for _, unit := range []string{"mo", "d", "w", "y"} {
+		number, ok := strings.CutSuffix(offset, unit)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			break
+		}
+		switch unit {
+		case "d":
+			return t.AddDate(0, 0, n), nil
+		case "w":
+			return t.AddDate(0, 0, 7*n), nil
+		case "mo":
+			return t.AddDate(0, n, 0), nil
+		}
+		return t.AddDate(n, 0, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time offset: %q", offset)
+}
+
+// parseWeekdays parses a comma-separated list of weekdays and weekday ranges, like "mon-fri" or "sat,sun".
+// Ranges can wrap around the week, e.g. "fri-mon".
+func parseWeekdays(value string) ([]bool, error) {
+	weekdays := make([]bool, 7)
+	for _, part := range strings.Split(value, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := parseWeekday(first)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if isRange {
+			if to, err = parseWeekday(last); err != nil {
+				return nil, err
+			}
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			weekdays[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return weekdays, nil
+}
+
+// parseWeekday parses a weekday by the first three letters of its name, e.g. "mon".
+func parseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()[:3]) {
+			return day, nil
+		}
 	}
-	g.logger.Debug("Returning current time")
-	return time.Now(), nil
+	return 0, fmt.Errorf("invalid weekday: %q", name)
+}
+
+// parseHours parses a range of hours, like "9-17" for times from 9:00 to 16:59.
+func parseHours(value string) ([2]int, error) {
+	first, last, ok := strings.Cut(value, "-")
+	from, err := strconv.Atoi(first)
+	if err != nil || !ok {
+		return [2]int{}, fmt.Errorf("invalid hours: %q", value)
+	}
+	to, err := strconv.Atoi(last)
+	if err != nil || from < 0 || to > 24 || from >= to {
+		return [2]int{}, fmt.Errorf("invalid hours: %q", value)
+	}
+	return [2]int{from, to}, nil
+}
+
+// truncateTime rounds t down to a multiple of the unit in its location.
+// Unlike time.Truncate, days and hours start at midnight and full hours of the location.
+func truncateTime(t time.Time, unit string) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	switch unit {
+	case "day":
+		hour, minute, second = 0, 0, 0
+	case "hour":
+		minute, second = 0, 0
+	case "minute":
+		second = 0
+	case "second":
+	default:
+		return t
+	}
+	return time.Date(year, month, day, hour, minute, second, 0, t.Location())
 }
 
 func (g *TimeGenerator) Validate() error {
 	g.logger.Debug("TimeGenerator validation passed")
 	return nil
 }
+
+// TimeStringGenerator generates times formatted as strings, for date fields of string types.
+type TimeStringGenerator struct {
+	time   Generator[time.Time]
+	layout string
+	BaseGenerator
+}
+
+// NewTimeStringGenerator creates a TimeStringGenerator using the "layout" tag,
+// a name from TimeLayouts or a time.Format layout, and the tags of NewTimeGenerator.
+func NewTimeStringGenerator(tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[string] {
+	layout := tags["layout"]
+	if named, ok := TimeLayouts[layout]; ok {
+		layout = named
+	}
+	if layout == "" {
+		logger.Error("Empty layout provided")
+		panic("empty layout provided")
+	}
+
+	logger.Debug("TimeStringGenerator created", "layout", layout)
+	return &TimeStringGenerator{NewTimeGenerator(tags, rand, logger), layout, BaseGenerator{rand, logger}}
+}
+
+// Evaluate returns a generated time formatted with the layout.
+func (g *TimeStringGenerator) Evaluate() (string, error) {
+	t, err := g.time.Evaluate()
+	if err != nil {
+		return "", err
+	}
+	result := t.Format(g.layout)
+	g.logger.Debug("Evaluate generated value", "value", result)
+	return result, nil
+}
+
+func (g *TimeStringGenerator) Validate() error {
+	return nil
+}
+
+// DurationGenerator generates time.Duration values
+// based on min and max values.
+type DurationGenerator struct {
+	min      time.Duration
+	max      time.Duration
+	truncate time.Duration
+	BaseGenerator
+}
+
+var durationUnits = map[string]time.Duration{
+	"day":    24 * time.Hour,
+	"hour":   time.Hour,
+	"minute": time.Minute,
+	"second": time.Second,
+}
+
+// NewDurationGenerator creates a new DurationGenerator using "min", "max" and "truncate" tags.
+// "min" and "max" are Go durations like "90s" or "1h30m", or a number of days or weeks like "7d" or "2w".
+// Defaults to durations from 0 to 24 hours. "truncate" can be "day", "hour", "minute" or "second".
+func NewDurationGenerator(tags map[string]string, rand *rand.Rand, logger *slog.Logger) Generator[time.Duration] {
+	g := &DurationGenerator{max: 24 * time.Hour, BaseGenerator: BaseGenerator{rand, logger}}
+
+	for name, bound := range map[string]*time.Duration{"min": &g.min, "max": &g.max} {
+		value, ok := tags[name]
+		if !ok {
+			continue
+		}
+		d, err := parseDuration(value)
+		if err != nil {
+			logger.Error("Failed to parse duration", name, value, "error", err)
+			panic(err)
+		}
+		*bound = d
+	}
+	if g.min > g.max {
+		logger.Error("Invalid duration range provided", "min", g.min, "max", g.max)
+		panic(fmt.Sprintf("invalid duration range provided: min %s is greater than max %s", g.min, g.max))
+	}
+
+	if value, ok := tags["truncate"]; ok {
+		unit, ok := durationUnits[value]
+		if !ok {
+			logger.Error("Invalid truncate provided", "truncate", value)
+			panic(fmt.Sprintf("invalid truncate provided: %q", value))
+		}
+		g.truncate = unit
+	}
+
+	logger.Debug("DurationGenerator created", "min", g.min, "max", g.max, "truncate", g.truncate)
+	return g
+}
+
+// Evaluate returns a random duration between min and max values.
+func (g *DurationGenerator) Evaluate() (time.Duration, error) {
+	result := g.min
+	if span := g.max - g.min; span > 0 {
+		result += time.Duration(g.rand.Int63n(int64(span)))
+	}
+	if g.truncate > 0 {
+		// truncation must not leave the range
+		if truncated := result.Truncate(g.truncate); truncated >= g.min {
+			result = truncated
+		}
+	}
+	g.logger.Debug("Evaluate generated value", "value", result)
+	return result, nil
+}
+
+// evaluateWithin returns a random duration within the range and the bounds.
+func (g *DurationGenerator) evaluateWithin(low, high any) (any, bool, error) {
+	min, max, ok := narrowRange(constant.MakeInt64(int64(g.min)), constant.MakeInt64(int64(g.max)), low, high)
+	if !ok {
+		return nil, false, nil
+	}
+	narrowed := &DurationGenerator{truncate: g.truncate, BaseGenerator: g.BaseGenerator}
+	from, _ := constant.Int64Val(min)
+	to, _ := constant.Int64Val(max)
+	narrowed.min, narrowed.max = time.Duration(from), time.Duration(to)
+	value, err := narrowed.Evaluate()
+	return value, true, err
+}
+
+// parseDuration parses a Go duration, or a number of days or weeks like "7d" or "2w".
+func parseDuration(value string) (time.Duration, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, nil
+	}
+	for unit, size := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(value, unit); ok {
+			if n, err := strconv.Atoi(number); err == nil {
+				return time.Duration(n) * size, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid duration: %q", value)
+}
+
+func (g *DurationGenerator) Validate() error {
+	return nil
+}
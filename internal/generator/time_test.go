@@ -41,3 +41,167 @@ func TestTimeGenerator_Ranges(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeGenerator_Bounds(t *testing.T) {
+	now := time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		from, to time.Time
+	}{
+		{
+			name: "past",
+			tags: map[string]string{"range": "past"},
+			from: now.AddDate(-1, 0, 0),
+			to:   now,
+		},
+		{
+			name: "absolute",
+			tags: map[string]string{"from": "2024-01-01", "to": "2024-01-31T23:59:59Z"},
+			from: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, time.January, 31, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name: "relative",
+			tags: map[string]string{"from": "-30d", "to": "-2h"},
+			from: now.AddDate(0, 0, -30),
+			to:   now.Add(-2 * time.Hour),
+		},
+		{
+			name: "overridden range",
+			tags: map[string]string{"range": "future", "to": "+1w"},
+			from: now,
+			to:   now.AddDate(0, 0, 7),
+		},
+		{
+			name: "only from",
+			tags: map[string]string{"from": "+6mo"},
+			from: now.AddDate(0, 6, 0),
+			to:   now.AddDate(1, 6, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tags["now"] = now.Format(time.RFC3339)
+			g := NewTimeGenerator(tt.tags, testRand(), testutils.TestLogger())
+			for range 100 {
+				val, _ := g.Evaluate()
+				if val.Before(tt.from) || val.After(tt.to) {
+					t.Fatalf("%s is not within %s and %s", val, tt.from, tt.to)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeGenerator_PinnedNow(t *testing.T) {
+	g := NewTimeGenerator(map[string]string{"now": "2025-06-15T12:34:56+02:00", "truncate": "hour"}, testRand(), testutils.TestLogger())
+	val, _ := g.Evaluate()
+	if want := "2025-06-15T12:00:00+02:00"; val.Format(time.RFC3339) != want {
+		t.Errorf("Evaluate() = %s, want %s", val.Format(time.RFC3339), want)
+	}
+}
+
+func TestTimeGenerator_Constraints(t *testing.T) {
+	tags := map[string]string{
+		"now":      "2025-06-15T12:00:00Z",
+		"range":    "past",
+		"tz":       "America/New_York",
+		"weekdays": "mon-fri",
+		"hours":    "9-17",
+		"truncate": "minute",
+	}
+	g := NewTimeGenerator(tags, testRand(), testutils.TestLogger())
+	for range 100 {
+		val, err := g.Evaluate()
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if val.Location().String() != "America/New_York" {
+			t.Fatalf("Unexpected location: %s", val.Location())
+		}
+		if val.Weekday() == time.Saturday || val.Weekday() == time.Sunday || val.Hour() < 9 || val.Hour() >= 17 {
+			t.Fatalf("%s is not within business hours", val)
+		}
+		if val.Second() != 0 || val.Nanosecond() != 0 {
+			t.Fatalf("%s is not truncated to minutes", val)
+		}
+	}
+
+	tags = map[string]string{"now": "2025-06-15T12:00:00Z", "from": "2025-06-14", "to": "2025-06-15", "weekdays": "mon-fri"}
+	if _, err := NewTimeGenerator(tags, testRand(), testutils.TestLogger()).Evaluate(); err == nil {
+		t.Errorf("Expected an error for a weekend range with weekdays=mon-fri")
+	}
+}
+
+func TestTimeGenerator_Invalid(t *testing.T) {
+	for _, tags := range []map[string]string{
+		{"range": "recent"},
+		{"from": "yesterday"},
+		{"from": "2025-01-02", "to": "2025-01-01"},
+		{"tz": "Mars/Olympus"},
+		{"truncate": "week"},
+		{"range": "past", "weekdays": "mon-fun"},
+		{"range": "past", "hours": "17-9"},
+		{"hours": "9-17"},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for %v", tags)
+				}
+			}()
+			NewTimeGenerator(tags, testRand(), testutils.TestLogger())
+		}()
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	weekdays, err := parseWeekdays("fri-mon,Wed")
+	if err != nil {
+		t.Fatalf("parseWeekdays() error = %v", err)
+	}
+	want := []bool{true, true, false, true, false, true, true}
+	for day, allowed := range want {
+		if weekdays[day] != allowed {
+			t.Errorf("%s allowed = %v, want %v", time.Weekday(day), weekdays[day], allowed)
+		}
+	}
+}
+
+func TestTimeStringGenerator_Layout(t *testing.T) {
+	tags := map[string]string{"now": "2025-06-15T12:00:00Z", "from": "2024-01-01", "to": "2024-12-31", "layout": "DateOnly"}
+	g := NewTimeStringGenerator(tags, testRand(), testutils.TestLogger())
+	val, _ := g.Evaluate()
+	if parsed, err := time.Parse(time.DateOnly, val); err != nil || parsed.Year() != 2024 {
+		t.Errorf("Unexpected date: %s", val)
+	}
+
+	tags["layout"] = "02.01.2006"
+	val, _ = NewTimeStringGenerator(tags, testRand(), testutils.TestLogger()).Evaluate()
+	if _, err := time.Parse("02.01.2006", val); err != nil {
+		t.Errorf("Unexpected date: %s", val)
+	}
+}
+
+func TestDurationGenerator_Range(t *testing.T) {
+	g := NewDurationGenerator(map[string]string{"min": "1m", "max": "2d", "truncate": "minute"}, testRand(), testutils.TestLogger())
+	for range 100 {
+		val, _ := g.Evaluate()
+		if val < time.Minute || val > 48*time.Hour || val%time.Minute != 0 {
+			t.Fatalf("Unexpected duration: %s", val)
+		}
+	}
+
+	for _, tags := range []map[string]string{{"min": "1h", "max": "1m"}, {"max": "soon"}, {"truncate": "week"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for %v", tags)
+				}
+			}()
+			NewDurationGenerator(tags, testRand(), testutils.TestLogger())
+		}()
+	}
+}
@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// maxUniqueAttempts limits the number of times fields are regenerated until their values are unique.
+const maxUniqueAttempts = 1000
+
+var ErrUniqueExhausted = errors.New("can not generate a unique value")
+
+// uniqueGroup is a set of fields whose values are unique across all generated instances,
+// like a single field with the "unique" tag or the fields of a composite key with "unique=group".
+type uniqueGroup struct {
+	name   string              // name of the group, or of the field for the "unique" tag without a value
+	fields []int               // indexes of the fields of the key, in declaration order
+	retry  []int               // indexes of the fields regenerated on duplicates, in evaluation order
+	seen   map[string]struct{} // keys of generated instances
+}
+
+// uniqueGroups returns the groups of fields with "unique" tags in the order of their first fields.
+// Fields derived from the fields of a group, like "expr=ID*10", are regenerated with the group,
+// as well as the fields a derived field of the group depends on.
+func uniqueGroups(fields []FieldGenerator, deps [][]int, order []int) []*uniqueGroup {
+	var groups []*uniqueGroup
+	for i, f := range fields {
+		name, ok := f.Field.MockTags["unique"]
+		if !ok {
+			continue
+		}
+		if name == "" {
+			name = f.Field.Name
+		}
+		j := slices.IndexFunc(groups, func(g *uniqueGroup) bool { return g.name == name })
+		if j < 0 {
+			groups = append(groups, &uniqueGroup{name: name, seen: make(map[string]struct{})})
+			j = len(groups) - 1
+		}
+		groups[j].fields = append(groups[j].fields, i)
+	}
+
+	position := make([]int, len(order)) // positions of fields in evaluation order
+	for pos, i := range order {
+		position[i] = pos
+	}
+	for _, g := range groups {
+		retry := slices.Clone(g.fields)
+		for k := 0; k < len(retry); k++ {
+			for _, j := range deps[retry[k]] {
+				if !slices.Contains(retry, j) {
+					retry = append(retry, j)
+				}
+			}
+		}
+		for changed := true; changed; {
+			changed = false
+			for i, fieldDeps := range deps {
+				if slices.Contains(retry, i) {
+					continue
+				}
+				if slices.ContainsFunc(fieldDeps, func(j int) bool { return slices.Contains(retry, j) }) {
+					retry = append(retry, i)
+					changed = true
+				}
+			}
+		}
+		sort.Slice(retry, func(a, b int) bool { return position[retry[a]] < position[retry[b]] })
+		g.retry = retry
+	}
+	return groups
+}
+
+// key returns the key of the group in the record. It returns false if any of the values is nil,
+// nil values are not compared like NULL in SQL unique constraints.
+func (g *uniqueGroup) key(record *Record) (string, bool) {
+	var key strings.Builder
+	for _, field := range g.fields {
+		value := record.Fields[field].Value
+		if value == nil {
+			return "", false
+		}
+		writeKey(&key, value)
+		key.WriteByte(';')
+	}
+	return key.String(), true
+}
+
+// writeKey appends a canonical encoding of a generated value to the key.
+// Nested records are encoded by their field values, so equal structs give equal keys,
+// and map entries are sorted, as maps with the same entries are equal in any order.
+func writeKey(key *strings.Builder, value any) {
+	switch value := value.(type) {
+	case *Record:
+		if value == nil {
+			key.WriteString("nil")
+			return
+		}
+		key.WriteByte('{')
+		for _, f := range value.Fields {
+			key.WriteString(f.Field.Name + ":")
+			writeKey(key, f.Value)
+			key.WriteByte(',')
+		}
+		key.WriteByte('}')
+	case []any:
+		key.WriteByte('[')
+		for _, elem := range value {
+			writeKey(key, elem)
+			key.WriteByte(',')
+		}
+		key.WriteByte(']')
+	case []MapEntry:
+		entries := make([]string, len(value))
+		for i, entry := range value {
+			var b strings.Builder
+			writeKey(&b, entry.Key)
+			b.WriteByte(':')
+			writeKey(&b, entry.Value)
+			entries[i] = b.String()
+		}
+		sort.Strings(entries)
+		key.WriteString("map[" + strings.Join(entries, ",") + "]")
+	default:
+		fmt.Fprintf(key, "%#v", value)
+	}
+}
+
+// duplicate reports whether the values of the group in the record are already generated.
+func (g *uniqueGroup) duplicate(record *Record) bool {
+	key, ok := g.key(record)
+	if !ok {
+		return false
+	}
+	_, seen := g.seen[key]
+	return seen
+}
+
+// add marks the values of the group in the record as generated.
+func (g *uniqueGroup) add(record *Record) {
+	if key, ok := g.key(record); ok {
+		g.seen[key] = struct{}{}
+	}
+}
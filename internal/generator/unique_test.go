@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestBuilder_Unique(t *testing.T) {
+	intType := &typeinfo.Type{Kind: typeinfo.Int}
+	tests := []struct {
+		name   string
+		fields []typeinfo.Field
+		// count is the number of distinct values, generating one more instance fails
+		count int
+	}{
+		{
+			name:   "int8",
+			fields: []typeinfo.Field{{Name: "ID", Type: &typeinfo.Type{Kind: typeinfo.Int8}, MockTags: map[string]string{"unique": ""}}},
+			count:  256,
+		},
+		{
+			name: "composite",
+			fields: []typeinfo.Field{
+				{Name: "Active", Type: &typeinfo.Type{Kind: typeinfo.Bool}, MockTags: map[string]string{"unique": "key"}},
+				{Name: "Level", Type: intType, MockTags: map[string]string{"min": "1", "max": "3", "unique": "key"}},
+				{Name: "Note", Type: intType, MockTags: map[string]string{"min": "0", "max": "1"}},
+			},
+			count: 6,
+		},
+		{
+			name: "derived",
+			fields: []typeinfo.Field{
+				{Name: "Code", Type: intType, MockTags: map[string]string{"expr": "ID*10", "unique": ""}},
+				{Name: "ID", Type: intType, MockTags: map[string]string{"min": "1", "max": "5"}},
+			},
+			count: 5,
+		},
+		{
+			name: "separate",
+			fields: []typeinfo.Field{
+				{Name: "A", Type: intType, MockTags: map[string]string{"min": "1", "max": "4", "unique": ""}},
+				{Name: "B", Type: intType, MockTags: map[string]string{"min": "1", "max": "9", "unique": ""}},
+			},
+			count: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ := &typeinfo.Type{Kind: typeinfo.Struct, Name: "T", Fields: tt.fields}
+			g, err := testBuilder(5).Build(typ, nil)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			seen := make(map[string]bool)
+			for range tt.count {
+				val, err := g.EvaluateAny()
+				if err != nil {
+					t.Fatalf("EvaluateAny() error = %v after %d instances", err, len(seen))
+				}
+				record := val.(*Record)
+				var key []any
+				for _, f := range record.Fields {
+					if _, ok := f.Field.MockTags["unique"]; ok {
+						key = append(key, f.Value)
+					}
+				}
+				if k := fmt.Sprint(key); seen[k] {
+					t.Fatalf("Duplicate values %s", k)
+				} else {
+					seen[k] = true
+				}
+			}
+
+			if _, err := g.EvaluateAny(); !errors.Is(err, ErrUniqueExhausted) {
+				t.Errorf("EvaluateAny() error = %v, want ErrUniqueExhausted", err)
+			}
+		})
+	}
+}
+
+func TestBuilder_UniqueStruct(t *testing.T) {
+	point := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Point", Fields: []typeinfo.Field{
+		{Name: "X", Type: &typeinfo.Type{Kind: typeinfo.Int}, MockTags: map[string]string{"min": "1", "max": "2"}},
+		{Name: "Y", Type: &typeinfo.Type{Kind: typeinfo.Int}, MockTags: map[string]string{"min": "1", "max": "2"}},
+	}}
+	typ := &typeinfo.Type{Kind: typeinfo.Struct, Name: "T", Fields: []typeinfo.Field{
+		{Name: "Point", Type: point, MockTags: map[string]string{"unique": ""}},
+	}}
+	g, err := testBuilder(5).Build(typ, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	seen := make(map[string]bool)
+	for range 4 {
+		val, err := g.EvaluateAny()
+		if err != nil {
+			t.Fatalf("EvaluateAny() error = %v after %d instances", err, len(seen))
+		}
+		k := fmt.Sprint(val.(*Record).Fields[0].Value.(*Record).Map())
+		if seen[k] {
+			t.Fatalf("Duplicate point %s", k)
+		}
+		seen[k] = true
+	}
+	if _, err := g.EvaluateAny(); !errors.Is(err, ErrUniqueExhausted) {
+		t.Errorf("EvaluateAny() error = %v, want ErrUniqueExhausted", err)
+	}
+}
+
+func TestUniqueGroup_Key(t *testing.T) {
+	field := typeinfo.Field{Name: "Value"}
+	group := &uniqueGroup{fields: []int{0}}
+	key := func(value any) string {
+		k, _ := group.key(&Record{Fields: []RecordField{{Field: field, Value: value}}})
+		return k
+	}
+	record := func(value any) *Record {
+		return &Record{Fields: []RecordField{{Field: field, Value: value}}}
+	}
+
+	if key(record(1)) != key(record(1)) || key(record(1)) == key(record(2)) {
+		t.Errorf("Records are not compared by their values: %s, %s", key(record(1)), key(record(2)))
+	}
+	if key([]any{record("a")}) != key([]any{record("a")}) {
+		t.Errorf("Slices of records are not compared by their values")
+	}
+	a := []MapEntry{{Key: "a", Value: 1}, {Key: "b", Value: 2}}
+	b := []MapEntry{{Key: "b", Value: 2}, {Key: "a", Value: 1}}
+	if key(a) != key(b) {
+		t.Errorf("Maps are compared by the order of their entries: %s, %s", key(a), key(b))
+	}
+	if _, ok := group.key(record(record(nil))); !ok {
+		t.Errorf("A record with a nil field is not a nil value")
+	}
+	if _, ok := group.key(&Record{Fields: []RecordField{{Field: field}}}); ok {
+		t.Errorf("Nil values are compared")
+	}
+}
+
+func TestBuilder_UniqueEnum(t *testing.T) {
+	status := &typeinfo.Type{Kind: typeinfo.String, PkgPath: "example.com/models", Name: "Status", Enum: []string{"active", "deleted"}}
+	typ := &typeinfo.Type{Kind: typeinfo.Struct, Name: "T", Fields: []typeinfo.Field{
+		{Name: "Status", Type: status, MockTags: map[string]string{"unique": ""}},
+	}}
+	g, err := testBuilder(5).Build(typ, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	var values []string
+	for range 2 {
+		val, _ := g.EvaluateAny()
+		values = append(values, val.(*Record).Fields[0].Value.(string))
+	}
+	if strings.Join(values, ",") != "active,deleted" && strings.Join(values, ",") != "deleted,active" {
+		t.Errorf("Unique enum values = %v, want both constants", values)
+	}
+}
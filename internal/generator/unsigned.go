@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"go/constant"
 	"log/slog"
 	"math"
 	"math/rand"
@@ -38,6 +39,8 @@ func NewUnsignedGenerator[T constraints.Unsigned](tags map[string]string, rand *
 		max = math.MaxUint32
 	case reflect.Uint64:
 		max = math.MaxUint64
+	case reflect.Uintptr:
+		max = uint64(^uintptr(0))
 	}
 
 	minVal, maxVal = min, max
@@ -76,6 +79,27 @@ func (g *UnsignedGenerator[T]) Evaluate() (T, error) {
 	return value, nil
 }
 
+// evaluateWithin returns a random unsigned integer within the range and the bounds.
+func (g *UnsignedGenerator[T]) evaluateWithin(low, high any) (any, bool, error) {
+	last := g.min // the greatest value of the range, max is excluded by Evaluate
+	if g.max > g.min {
+		last = g.max - 1
+	}
+	min, max, ok := narrowRange(constant.MakeUint64(g.min), constant.MakeUint64(last), low, high)
+	if !ok {
+		return nil, false, nil
+	}
+	from, _ := constant.Uint64Val(min)
+	to, _ := constant.Uint64Val(max)
+	r := g.rand.Uint64()
+	if span := to - from + 1; span > 0 { // zero for the full uint64 range
+		r %= span
+	}
+	value := T(from + r)
+	g.logger.Debug("Evaluated unsigned value", "value", value, "min", from, "max", to)
+	return value, true, nil
+}
+
 func unsignedClamp(value, min, max uint64) uint64 {
 	if value < min {
 		return min
@@ -16,3 +16,9 @@ type GenericGenerator[T any] struct {
 func (g *GenericGenerator[T]) EvaluateAny() (any, error) {
 	return g.impl.Evaluate()
 }
+
+// bounded returns the wrapped generator if its range can be narrowed, see ConstraintGenerator.
+func (g *GenericGenerator[T]) bounded() boundedGenerator {
+	bounded, _ := g.impl.(boundedGenerator)
+	return bounded
+}
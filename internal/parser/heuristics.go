@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// DefaultFieldRules choose mock tags of untagged fields by their names and types,
+// so that e.g. an untagged "Email string" field gets realistic emails.
+// Rules are checked in order, the first matching rule wins.
+var DefaultFieldRules = []config.FieldRule{
+	{Name: `email$`, Types: []string{"string"}, Tags: "kind=email"},
+	{Name: `^(first|given)name$`, Types: []string{"string"}, Tags: "kind=first_name"},
+	{Name: `^(last|sur|family)name$`, Types: []string{"string"}, Tags: "kind=last_name"},
+	{Name: `^(full|display)?name$`, Types: []string{"string"}, Tags: "kind=name"},
+	{Name: `^(username|login|nick(name)?)$`, Types: []string{"string"}, Tags: "kind=username"},
+	{Name: `(phone|mobile)(number)?$`, Types: []string{"string"}, Tags: "kind=phone"},
+	{Name: `(url|uri|website|homepage)$`, Types: []string{"string"}, Tags: "kind=url"},
+	{Name: `^(domain|host(name)?)$`, Types: []string{"string"}, Tags: "kind=domain"},
+	{Name: `^(ip|ipv4|ipaddr(ess)?)$`, Types: []string{"string"}, Tags: "kind=ipv4"},
+	{Name: `^ipv6(addr(ess)?)?$`, Types: []string{"string"}, Tags: "kind=ipv6"},
+	{Name: `^mac(addr(ess)?)?$`, Types: []string{"string"}, Tags: "kind=mac"},
+	{Name: `^(street(address)?|address(line1?)?)$`, Types: []string{"string"}, Tags: "kind=address"},
+	{Name: `^(home|billing|shipping)?(city|town)$`, Types: []string{"string"}, Tags: "kind=city"},
+	{Name: `^country(code)?$`, Types: []string{"string"}, Tags: "kind=country_code"},
+	{Name: `^(post|postal|zip)(code)?$`, Types: []string{"string"}, Tags: "kind=postcode"},
+	{Name: `^(company|employer|organi[sz]ation)(name)?$`, Types: []string{"string"}, Tags: "kind=company"},
+	{Name: `^(jobtitle|occupation|position)$`, Types: []string{"string"}, Tags: "kind=job_title"},
+	{Name: `^(description|bio|about|summary|comment|notes?)$`, Types: []string{"string"}, Tags: "kind=sentences"},
+	{Name: `colou?r$`, Types: []string{"string"}, Tags: "kind=hex_color"},
+	{Name: `^(credit)?card(number|no)$`, Types: []string{"string"}, Tags: "kind=credit_card"},
+	{Name: `iban$`, Types: []string{"string"}, Tags: "kind=iban"},
+	{Name: `slug$`, Types: []string{"string"}, Tags: "kind=slug"},
+	{Name: `^(created|updated|deleted|modified|registered|published)(at|on|date)?$`, Types: []string{"time.Time"}, Tags: "range=past"},
+	{Name: `^(birth(date|day)|dob|dateofbirth)$`, Types: []string{"time.Time"}, Tags: "from=-80y;to=-18y;truncate=day"},
+	{Name: `^(expires|expiry|expiration|due)(at|on|date)?$`, Types: []string{"time.Time"}, Tags: "range=future"},
+	{Name: `^(timeout|duration|ttl|interval|delay)$`, Types: []string{"time.Duration"}, Tags: "min=1s;max=1h;truncate=second"},
+	{Name: `(price|amount|cost|total|balance|salary)$`, Types: []string{"int*", "uint*", "float*"}, Tags: "min=1;max=10000"},
+	{Name: `^age$`, Types: []string{"int*", "uint*"}, Tags: "min=18;max=90"},
+	{Name: `^(quantity|qty|count)$`, Types: []string{"int*", "uint*"}, Tags: "min=1;max=100"},
+	{Name: `^(rating|stars)$`, Types: []string{"int*", "uint*", "float*"}, Tags: "min=1;max=5"},
+	{Name: `^(percent(age)?|progress)$`, Types: []string{"int*", "uint*", "float*"}, Tags: "min=0;max=100"},
+	{Name: `^(lat|latitude)$`, Types: []string{"float*"}, Tags: "min=-90;max=90"},
+	{Name: `^(lng|lon|longitude)$`, Types: []string{"float*"}, Tags: "min=-180;max=180"},
+	{Name: `^port$`, Types: []string{"int*", "uint*"}, Tags: "min=1;max=65535"},
+	{Name: `(^|_)(?i:id)$|[a-z0-9]I[Dd]$`, CaseSensitive: true, Types: []string{"int*", "uint*"}, Tags: "min=1;max=1000000"},
+}
+
+// fieldRule is a compiled config.FieldRule.
+type fieldRule struct {
+	name          *regexp.Regexp
+	caseSensitive bool
+	types         []string
+	tags          map[string]string
+}
+
+// compileFieldRules compiles the configured rules followed by DefaultFieldRules.
+// It returns no rules if heuristics are disabled.
+func compileFieldRules(cfg config.FieldsConfig) ([]fieldRule, error) {
+	if cfg.DisableHeuristics {
+		return nil, nil
+	}
+	rules := make([]fieldRule, 0, len(cfg.Rules)+len(DefaultFieldRules))
+	for _, rule := range append(cfg.Rules[:len(cfg.Rules):len(cfg.Rules)], DefaultFieldRules...) {
+		name, err := regexp.Compile(rule.Name)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fieldRule{name, rule.CaseSensitive, rule.Types, parseMockTags(rule.Tags)})
+	}
+	return rules, nil
+}
+
+// matches reports whether the rule applies to a field of the given name and type.
+// Types with declared constants are matched only by their qualified names, like "time.Duration",
+// as their values are otherwise chosen from the constants.
+func (r fieldRule) matches(fieldName string, t *typeinfo.Type) bool {
+	if !r.caseSensitive {
+		fieldName = normalizeFieldName(fieldName)
+	}
+	if !r.name.MatchString(fieldName) {
+		return false
+	}
+	base, _ := t.Deref()
+	enum := len(base.Enum) > 1
+	if len(r.types) == 0 {
+		return !enum
+	}
+	for _, pattern := range r.types {
+		if ok, _ := path.Match(pattern, base.QualifiedName()); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, base.Kind.String()); ok && base.Kind.IsBasic() && !enum {
+			return true
+		}
+	}
+	return false
+}
+
+// heuristicTags returns the tags of the first rule matching an untagged field.
+func (p *Parser) heuristicTags(fieldName string, t *typeinfo.Type) (map[string]string, bool) {
+	for _, rule := range p.rules {
+		if rule.matches(fieldName, t) {
+			p.logger.Debug("Field matched heuristic rule", "field", fieldName, "rule", rule.name, "mockTags", rule.tags)
+			tags := make(map[string]string, len(rule.tags))
+			for key, value := range rule.tags {
+				tags[key] = value
+			}
+			return tags, true
+		}
+	}
+	return nil, false
+}
+
+// fieldTags returns the mock tags of a field. Untagged fields, and fields tagged only with relations
+// to other fields like "after=CreatedAt", get the tags of the first matching rule as well.
+func (p *Parser) fieldTags(fieldName string, t *typeinfo.Type, mockTags map[string]string) map[string]string {
+	for key := range mockTags {
+		if !slices.Contains(generator.RelationTags, key) {
+			return mockTags
+		}
+	}
+	tags, ok := p.heuristicTags(fieldName, t)
+	if !ok {
+		return mockTags
+	}
+	maps.Copy(tags, mockTags)
+	return tags
+}
+
+// normalizeFieldName lower cases the name and removes underscores,
+// so that "FirstName", "firstName" and "first_name" are matched the same way.
+func normalizeFieldName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "")
+}
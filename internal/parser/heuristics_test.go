@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestParser_Heuristics(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type Customer struct {
+		ID        uint64
+		FirstName string
+		Email     *string
+		Nickname  string `mock:"prefix=nick_"`
+		SKU       string
+		CreatedAt time.Time
+		UpdatedAt time.Time `mock:"after=CreatedAt"`
+		Price     float64
+		Age       int
+		Address   Address
+		Color     int
+		UserID    int
+		OrderId   int
+		Item_id   int
+		Paid      int
+		Valid     int
+		Void      int
+	}
+
+	tests := []struct {
+		name   string
+		fields config.FieldsConfig
+		want   map[string]map[string]string
+	}{
+		{
+			name:   "default rules",
+			fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll},
+			want: map[string]map[string]string{
+				"ID":        {"min": "1", "max": "1000000"},
+				"FirstName": {"kind": "first_name"},
+				"Email":     {"kind": "email"},
+				"Nickname":  {"prefix": "nick_"},
+				"SKU":       {},
+				"CreatedAt": {"range": "past"},
+				"UpdatedAt": {"range": "past", "after": "CreatedAt"},
+				"Price":     {"min": "1", "max": "10000"},
+				"Age":       {"min": "18", "max": "90"},
+				"Address":   {},
+				"Color":     {},
+				"UserID":    {"min": "1", "max": "1000000"},
+				"OrderId":   {"min": "1", "max": "1000000"},
+				"Item_id":   {"min": "1", "max": "1000000"},
+				"Paid":      {},
+				"Valid":     {},
+				"Void":      {},
+			},
+		},
+		{
+			name: "configured rules take precedence",
+			fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll, Rules: []config.FieldRule{
+				{Name: `^sku$`, Tags: "prefix=SKU-;len=6"},
+				{Name: `^age$`, Types: []string{"int*"}, Tags: "min=1;max=12"},
+			}},
+			want: map[string]map[string]string{
+				"SKU": {"prefix": "SKU-", "len": "6"},
+				"Age": {"min": "1", "max": "12"},
+			},
+		},
+		{
+			name:   "disabled",
+			fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll, DisableHeuristics: true},
+			want: map[string]map[string]string{
+				"FirstName": {},
+				"CreatedAt": {},
+				"UpdatedAt": {"after": "CreatedAt"},
+				"Nickname":  {"prefix": "nick_"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser(&config.Config{Fields: tt.fields}, testutils.TestLogger())
+			desc := p.DescribeType(reflect.TypeOf(Customer{}))
+			for _, field := range desc.Fields {
+				want, ok := tt.want[field.Name]
+				if ok && !compareMaps(field.MockTags, want) {
+					t.Errorf("%s tags = %v, want %v", field.Name, field.MockTags, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeFieldName(t *testing.T) {
+	for _, name := range []string{"FirstName", "firstName", "first_name", "FIRST_NAME"} {
+		if got := normalizeFieldName(name); got != "firstname" {
+			t.Errorf("normalizeFieldName(%q) = %q, want %q", name, got, "firstname")
+		}
+	}
+}
+
+func TestFieldRule_EnumTypes(t *testing.T) {
+	rules, err := compileFieldRules(config.FieldsConfig{Rules: []config.FieldRule{{Name: `^anything$`, Tags: "min=1"}}})
+	if err != nil {
+		t.Fatalf("compileFieldRules() error = %v", err)
+	}
+	p := &Parser{rules: rules, logger: testutils.TestLogger()}
+
+	status := &typeinfo.Type{Kind: typeinfo.Int, PkgPath: "example.com/models", Name: "Status", Enum: []string{"1", "2"}}
+	duration := &typeinfo.Type{Kind: typeinfo.Int64, PkgPath: "time", Name: "Duration", Enum: []string{"1", "1000"}}
+
+	if tags, ok := p.heuristicTags("StatusID", status); ok {
+		t.Errorf("Enum types should not match rules by kind, got %v", tags)
+	}
+	if tags, ok := p.heuristicTags("Anything", status); ok {
+		t.Errorf("Enum types should not match rules without types, got %v", tags)
+	}
+	if tags, ok := p.heuristicTags("Timeout", duration); !ok || tags["max"] != "1h" {
+		t.Errorf("Enum types should match rules by qualified name, got %v", tags)
+	}
+}
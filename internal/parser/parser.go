@@ -1,10 +1,14 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
-	"go/parser"
+	"go/build"
 	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
@@ -12,31 +16,111 @@ import (
 	"log/slog"
 
 	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+	"golang.org/x/tools/go/packages"
 )
 
+// loadMode is the set of information requested from go/packages.
+// Dependencies are type-checked from source so that named types
+// declared in imported packages can always be resolved.
+const loadMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedSyntax |
+	packages.NeedTypes |
+	packages.NeedTypesInfo |
+	packages.NeedImports |
+	packages.NeedDeps
+
 type Parser struct {
-	config *config.Config
-	logger *slog.Logger
+	config    *config.Config
+	logger    *slog.Logger
+	described map[string]*typeinfo.Type       // named type -> descriptor, so that recursive types share descriptors
+	reflected map[reflect.Type]*typeinfo.Type // same as described, for types obtained by reflection
+	rules     []fieldRule                     // rules choosing mock tags of untagged fields
 }
 
+// NewParser creates a new Parser. It panics if a configured field rule is not a valid regular expression.
 func NewParser(cfg *config.Config, logger *slog.Logger) *Parser {
-	return &Parser{config: cfg, logger: logger}
+	rules, err := compileFieldRules(cfg.Fields)
+	if err != nil {
+		logger.Error("Failed to compile field rules", "error", err)
+		panic(err)
+	}
+	return &Parser{
+		config:    cfg,
+		logger:    logger,
+		described: make(map[string]*typeinfo.Type),
+		reflected: make(map[reflect.Type]*typeinfo.Type),
+		rules:     rules,
+	}
 }
 
-// ParseFile parses the file at the configured InputPath and returns a map of struct names to their fields.
-func (p *Parser) ParseFile() (map[string][]StructField, error) {
-	p.logger.Debug("Starting file parsing", "inputPath", p.config.InputPath)
-	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, p.config.InputPath, nil, parser.ParseComments)
+// Parse loads the package(s) at the configured InputPath with full type information
+// and returns a map of struct names to their type descriptors.
+//
+// InputPath can be a single Go file, a package directory or a package pattern such as "./...".
+// When a single file is given, the whole package it belongs to is type-checked,
+// but only structs declared in that file are returned.
+func (p *Parser) Parse() (map[string]*typeinfo.Type, error) {
+	p.logger.Debug("Starting package loading", "inputPath", p.config.InputPath)
+
+	dir, patterns, onlyFile, err := p.patterns()
+	if err != nil {
+		p.logger.Error("Failed to resolve input path", "inputPath", p.config.InputPath, "error", err)
+		return nil, err
+	}
+
+	// packages are loaded within the module of the input, not of the working directory
+	pkgs, err := p.load(dir, patterns, onlyFile)
 	if err != nil {
-		p.logger.Error("Failed to parse file", "inputPath", p.config.InputPath, "error", err)
+		p.logger.Error("Failed to load packages", "inputPath", p.config.InputPath, "error", err)
 		return nil, err
 	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages found for %s", p.config.InputPath)
+	}
+
+	var loadErrs []error
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			p.logger.Error("Package loaded with errors", "package", pkg.PkgPath, "error", pkgErr)
+			loadErrs = append(loadErrs, pkgErr)
+		}
+	}
+	if len(loadErrs) > 0 {
+		return nil, fmt.Errorf("failed to load %s: %w", p.config.InputPath, errors.Join(loadErrs...))
+	}
+
+	structs := make(map[string]*typeinfo.Type) // structName -> struct type
+
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || pkg.TypesInfo == nil {
+			p.logger.Warn("Package has no type information; skipping", "package", pkg.PkgPath)
+			continue
+		}
+
+		for _, file := range pkg.Syntax {
+			fileName := pkg.Fset.Position(file.Pos()).Filename
+			if onlyFile != "" && fileName != onlyFile {
+				continue
+			}
+			p.parseFile(pkg, file, structs)
+		}
+	}
+
+	if len(structs) == 0 {
+		p.logger.Error("No structs found", "inputPath", p.config.InputPath, "structNames", p.config.Generation.StructNames)
+		return nil, fmt.Errorf("no structs found in %s", p.config.InputPath)
+	}
 
-	structs := make(map[string][]StructField) // structName -> fields
+	p.logger.Info("Packages parsed successfully", "packageCount", len(pkgs), "structCount", len(structs))
+	return structs, nil
+}
 
+// parseFile collects all struct declarations of a single file into structs.
+func (p *Parser) parseFile(pkg *packages.Package, file *ast.File, structs map[string]*typeinfo.Type) {
 	// Iterate over all declarations in the file.
-	for _, decl := range node.Decls {
+	for _, decl := range file.Decls {
 		genDecl, ok := decl.(*ast.GenDecl)
 		if !ok || genDecl.Tok != token.TYPE {
 			continue // skip non-type declarations
@@ -55,43 +139,130 @@ func (p *Parser) ParseFile() (map[string][]StructField, error) {
 				continue
 			}
 
-			structType, ok := typeSpec.Type.(*ast.StructType)
+			obj, ok := pkg.TypesInfo.Defs[typeSpec.Name].(*types.TypeName)
 			if !ok {
+				p.logger.Warn("No type information for declaration; skipping", "structName", structName)
+				continue
+			}
+
+			if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
 				p.logger.Warn("TypeSpec is not a struct; skipping", "structName", structName)
 				continue // skip if not a struct
 			}
 
-			fields := p.extractFields(structType)
-			structs[structName] = fields
-			p.logger.Debug("Parsed struct", "structName", structName, "fieldCount", len(fields))
+			if typeSpec.TypeParams != nil {
+				p.logger.Warn("Generic structs can not be generated directly; skipping", "structName", structName)
+				continue
+			}
+
+			if _, exists := structs[structName]; exists {
+				p.logger.Warn("Struct with the same name already parsed; skipping", "structName", structName, "package", pkg.PkgPath)
+				continue
+			}
+
+			structType := p.describe(obj.Type())
+			structs[structName] = structType
+			p.logger.Debug("Parsed struct", "structName", structName, "package", pkg.PkgPath, "fieldCount", len(structType.Fields))
 		}
 	}
-	p.logger.Info("File parsed successfully", "structCount", len(structs))
-	return structs, nil
 }
 
-func (p *Parser) extractFields(structType *ast.StructType) []StructField {
-	var fields []StructField
-	for _, field := range structType.Fields.List {
-		fieldType := fmt.Sprintf("%s", field.Type)
-
-		var mockTags map[string]string
-		if field.Tag != nil {
-			tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
-			mockTags = parseMockTags(tag.Get("mock"))
+// extractFields describes the fields of a struct that should be generated.
+// Blank fields can not be set, their tag is returned as the tag of the struct.
+func (p *Parser) extractFields(structType *types.Struct) (fields []typeinfo.Field, structTag reflect.StructTag) {
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		tag := reflect.StructTag(structType.Tag(i))
+		if field.Name() == "_" {
+			structTag = tag
+			continue
 		}
+		mockTags := parseMockTags(tag.Get("mock"))
 
 		if p.shouldAddField(mockTags) {
-			for _, name := range field.Names {
-				fields = append(fields, StructField{
-					Name:     name.Name,
-					Type:     fieldType,
-					MockTags: mockTags,
-				})
+			fieldType := p.describe(field.Type())
+			mockTags = p.fieldTags(field.Name(), fieldType, mockTags)
+			fields = append(fields, typeinfo.Field{
+				Name:     field.Name(),
+				Type:     fieldType,
+				Tag:      tag,
+				MockTags: mockTags,
+				Embedded: field.Embedded(),
+			})
+		}
+	}
+	return fields, structTag
+}
+
+// patterns returns the directory to load packages from and the patterns to load.
+// A package directory is loaded from itself, a pattern like "models/..." from its base directory,
+// and a single file with the package of its directory, so that the result does not depend
+// on the working directory. Other patterns, like import paths, are loaded from the working directory.
+// For a single file onlyFile is its absolute path.
+func (p *Parser) patterns() (dir string, patterns []string, onlyFile string, err error) {
+	input := p.config.InputPath
+	if input == "" {
+		return "", nil, "", errors.New("input path is empty")
+	}
+
+	info, err := os.Stat(input)
+	if err == nil && info.IsDir() {
+		return input, []string{"."}, "", nil
+	}
+	if err != nil {
+		if base, ok := strings.CutSuffix(filepath.ToSlash(input), "/..."); ok {
+			if info, err := os.Stat(base); err == nil && info.IsDir() {
+				return base, []string{"./..."}, "", nil
 			}
 		}
+		return "", []string{input}, "", nil
+	}
+
+	onlyFile, err = filepath.Abs(input)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	return filepath.Dir(onlyFile), []string{"."}, onlyFile, nil
+}
+
+// load loads the packages matching the patterns from dir.
+// A single file is loaded with the package of its directory, so that build constraints of its sibling files,
+// like "//go:build ignore" of a generator, apply. Outside of a module, or if the file itself is excluded
+// by build constraints, it is loaded together with the sibling files matching the build constraints.
+func (p *Parser) load(dir string, patterns []string, onlyFile string) ([]*packages.Package, error) {
+	cfg := &packages.Config{Mode: loadMode, Dir: dir}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if onlyFile == "" || err == nil && containsFile(pkgs, onlyFile) {
+		return pkgs, err
+	}
+	p.logger.Debug("Loading file with its sibling files", "file", onlyFile, "error", err)
+
+	siblings, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	files := []string{onlyFile}
+	for _, sibling := range siblings {
+		if sibling == onlyFile || strings.HasSuffix(sibling, "_test.go") {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, filepath.Base(sibling)); err != nil || !ok {
+			continue
+		}
+		files = append(files, sibling)
+	}
+	return packages.Load(cfg, files...)
+}
+
+// containsFile reports whether the file is one of the files of the packages.
+func containsFile(pkgs []*packages.Package, file string) bool {
+	for _, pkg := range pkgs {
+		if slices.Contains(pkg.GoFiles, file) {
+			return true
+		}
 	}
-	return fields
+	return false
 }
 
 func (p *Parser) shouldParseStruct(structName string) bool {
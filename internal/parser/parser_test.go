@@ -1,26 +1,41 @@
 package parser
 
 import (
-	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/maksemen2/mockfactory/internal/config"
 	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
 )
 
-func createTempFile(content string) (string, func()) {
-	tmpFile, err := os.CreateTemp("", "testfile-*.go")
+var (
+	intType    = &typeinfo.Type{Kind: typeinfo.Int}
+	stringType = &typeinfo.Type{Kind: typeinfo.String}
+)
+
+// createTempPackage writes files into a new temporary directory,
+// so that they are loaded as a single package.
+func createTempPackage(files map[string]string) (string, func()) {
+	dir, err := os.MkdirTemp("", "testpkg-*")
 	if err != nil {
 		panic(err)
 	}
 
-	if _, err := tmpFile.WriteString(content); err != nil {
-		panic(err)
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			panic(err)
+		}
 	}
-	tmpFile.Close()
 
-	return tmpFile.Name(), func() { os.Remove(tmpFile.Name()) }
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func createTempFile(content string) (string, func()) {
+	dir, cleanup := createTempPackage(map[string]string{"testfile.go": content})
+	return filepath.Join(dir, "testfile.go"), cleanup
 }
 
 func compareMaps(a, b map[string]string) bool {
@@ -35,7 +50,27 @@ func compareMaps(a, b map[string]string) bool {
 	return true
 }
 
-func TestParser_ParseFile(t *testing.T) {
+// compareStructs compares parsed structs by field names, resolved types and tags.
+func compareStructs(a map[string]*typeinfo.Type, b map[string][]typeinfo.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, aType := range a {
+		aFields := aType.Fields
+		bFields, ok := b[name]
+		if !ok || len(aFields) != len(bFields) {
+			return false
+		}
+		for i := range aFields {
+			if aFields[i].Name != bFields[i].Name || !reflect.DeepEqual(aFields[i].Type, bFields[i].Type) || !compareMaps(aFields[i].MockTags, bFields[i].MockTags) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestParser_Parse(t *testing.T) {
 	testContent := `
 package testdata
 
@@ -56,20 +91,20 @@ type Account struct {
 	tests := []struct {
 		name        string
 		config      *config.Config
-		wantStructs map[string][]StructField
+		wantStructs map[string][]typeinfo.Field
 	}{
 		{
 			name:   "parse all structs",
-			config: &config.Config{InputPath: filePath, Fields: config.FieldsConfig{config.IncludeAll}},
-			wantStructs: map[string][]StructField{
+			config: &config.Config{InputPath: filePath, Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}},
+			wantStructs: map[string][]typeinfo.Field{
 				"User": {
-					{Name: "ID", Type: "int", MockTags: map[string]string{"min": "10", "max": "20"}},
-					{Name: "Name", Type: "string", MockTags: map[string]string{"ignore": ""}},
-					{Name: "Email", Type: "string", MockTags: map[string]string{}},
+					{Name: "ID", Type: intType, MockTags: map[string]string{"min": "10", "max": "20"}},
+					{Name: "Name", Type: stringType, MockTags: map[string]string{"ignore": ""}},
+					{Name: "Email", Type: stringType, MockTags: map[string]string{"kind": "email"}},
 				},
 				"Account": {
-					{Name: "ID", Type: "int", MockTags: map[string]string{}},
-					{Name: "Number", Type: "string", MockTags: map[string]string{}},
+					{Name: "ID", Type: intType, MockTags: map[string]string{"min": "1", "max": "1000000"}},
+					{Name: "Number", Type: stringType, MockTags: map[string]string{}},
 				},
 			},
 		},
@@ -80,13 +115,13 @@ type Account struct {
 				Generation: config.GenerationConfig{
 					StructNames: []string{"User"},
 				},
-				Fields: config.FieldsConfig{config.IncludeAll},
+				Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll, DisableHeuristics: true},
 			},
-			wantStructs: map[string][]StructField{
+			wantStructs: map[string][]typeinfo.Field{
 				"User": {
-					{Name: "ID", Type: "int", MockTags: map[string]string{"min": "10", "max": "20"}},
-					{Name: "Name", Type: "string", MockTags: map[string]string{"ignore": ""}},
-					{Name: "Email", Type: "string", MockTags: map[string]string{}},
+					{Name: "ID", Type: intType, MockTags: map[string]string{"min": "10", "max": "20"}},
+					{Name: "Name", Type: stringType, MockTags: map[string]string{"ignore": ""}},
+					{Name: "Email", Type: stringType, MockTags: map[string]string{}},
 				},
 			},
 		},
@@ -95,16 +130,419 @@ type Account struct {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := NewParser(tt.config, testutils.TestLogger())
-			got, err := p.ParseFile()
+			got, err := p.Parse()
 
 			if err != nil {
-				t.Errorf("ParseFile() error = %v", err)
+				t.Errorf("Parse() error = %v", err)
 				return
 			}
 
-			if fmt.Sprintf("%v", got) != fmt.Sprintf("%v", tt.wantStructs) {
-				t.Errorf("ParseFile() got = %v, want = %v", got, tt.wantStructs)
+			if !compareStructs(got, tt.wantStructs) {
+				t.Errorf("Parse() got = %v, want = %v", got, tt.wantStructs)
+			}
+		})
+	}
+}
+
+func TestParser_ResolvesSiblingFiles(t *testing.T) {
+	dir, cleanup := createTempPackage(map[string]string{
+		"user.go": `
+package testdata
+
+import t "time"
+
+type User struct {
+	ID        Identifier
+	CreatedAt t.Time
+}
+`,
+		"types.go": `
+package testdata
+
+type Identifier int64
+
+type Account struct {
+	Number string
+}
+`,
+	})
+	defer cleanup()
+
+	cfg := &config.Config{InputPath: filepath.Join(dir, "user.go"), Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+	got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if _, ok := got["Account"]; ok {
+		t.Errorf("Parse() returned struct from sibling file")
+	}
+
+	fields := got["User"].Fields
+	if len(fields) != 2 {
+		t.Fatalf("Parse() got %d fields, want 2", len(fields))
+	}
+	if fields[0].Type.Name != "Identifier" || fields[0].Type.Kind != typeinfo.Int64 {
+		t.Errorf("ID resolved to %s (%s)", fields[0].Type, fields[0].Type.Kind)
+	}
+	if fields[1].Type.QualifiedName() != "time.Time" {
+		t.Errorf("CreatedAt resolved to %s, want time.Time", fields[1].Type)
+	}
+}
+
+func TestParser_ParseDirectory(t *testing.T) {
+	dir, cleanup := createTempPackage(map[string]string{
+		"go.mod": "module example.com/testdata\n\ngo 1.23\n",
+		"user.go": `
+package testdata
+
+type User struct {
+	ID int
+}
+`,
+		"account.go": `
+package testdata
+
+type Account struct {
+	Owner User
+}
+`,
+	})
+	defer cleanup()
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{InputPath: "./...", Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+	got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Parse() got %d structs, want 2", len(got))
+	}
+	if got["Account"].Fields[0].Type.QualifiedName() != "example.com/testdata.User" {
+		t.Errorf("Owner resolved to %s, want example.com/testdata.User", got["Account"].Fields[0].Type)
+	}
+}
+
+func TestParser_WorkingDirectory(t *testing.T) {
+	dir, cleanup := createTempPackage(map[string]string{
+		"go.mod": "module example.com/testdata\n\ngo 1.23\n",
+		"user.go": `
+package testdata
+
+type User struct {
+	ID int
+}
+`,
+	})
+	defer cleanup()
+
+	// the working directory is the module of the test, not of the input
+	for _, input := range []string{dir, filepath.Join(dir, "..."), filepath.Join(dir, "user.go")} {
+		cfg := &config.Config{InputPath: input, Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+		got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", input, err)
+		}
+		if got["User"] == nil {
+			t.Errorf("Parse(%s) = %v, want User", input, got)
+		}
+	}
+}
+
+func TestParser_BuildConstraints(t *testing.T) {
+	files := map[string]string{
+		"user.go": `
+package testdata
+
+type User struct {
+	ID int
+}
+`,
+		"gen.go": `//go:build ignore
+
+package main
+
+func main() {}
+`,
+	}
+	for _, module := range []bool{false, true} {
+		if module {
+			files["go.mod"] = "module example.com/testdata\n\ngo 1.23\n"
+		}
+		dir, cleanup := createTempPackage(files)
+		defer cleanup()
+
+		cfg := &config.Config{InputPath: filepath.Join(dir, "user.go"), Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+		got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+		if err != nil {
+			t.Fatalf("Parse() in module %t error = %v", module, err)
+		}
+		if got["User"] == nil {
+			t.Errorf("Parse() in module %t = %v, want User", module, got)
+		}
+	}
+}
+
+func TestParser_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		structs []string
+	}{
+		{
+			name: "load errors",
+			files: map[string]string{"user.go": `
+package testdata
+
+import "example.com/missing"
+
+type User struct {
+	Broken missing.Type
+}
+`},
+		},
+		{
+			name:  "no structs",
+			files: map[string]string{"consts.go": "package testdata\n\nconst Answer = 42\n"},
+		},
+		{
+			name:    "no selected structs",
+			files:   map[string]string{"user.go": "package testdata\n\ntype User struct{ ID int }\n"},
+			structs: []string{"Order"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, cleanup := createTempPackage(tt.files)
+			defer cleanup()
+
+			cfg := &config.Config{InputPath: dir, Generation: config.GenerationConfig{StructNames: tt.structs}}
+			if got, err := NewParser(cfg, testutils.TestLogger()).Parse(); err == nil {
+				t.Errorf("Parse() = %v, want an error", got)
 			}
 		})
 	}
 }
+
+func TestParser_TypeDescriptors(t *testing.T) {
+	filePath, cleanup := createTempFile(`
+package testdata
+
+import (
+	t "time"
+
+	"github.com/google/uuid"
+)
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type Timestamp = t.Time
+
+type Model struct {
+	CreatedAt Timestamp
+	DeletedAt **t.Time
+	ID        uuid.UUID
+	Tags      []string
+	Matrix    [2][3]int8
+	Labels    map[string]*int
+	Pair      Pair[string, int]
+	Any       interface{}
+	Events    chan int
+}
+`)
+	defer cleanup()
+
+	cfg := &config.Config{InputPath: filePath, Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+	got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]string{
+		"CreatedAt": "time.Time",
+		"DeletedAt": "**time.Time",
+		"ID":        "github.com/google/uuid.UUID",
+		"Tags":      "[]string",
+		"Matrix":    "[2][3]int8",
+		"Labels":    "map[string]*int",
+		"Pair":      "command-line-arguments.Pair[string,int]",
+		"Any":       "interface{...}",
+		"Events":    "invalid",
+	}
+
+	fields := got["Model"].Fields
+	if len(fields) != len(want) {
+		t.Fatalf("Parse() got %d fields, want %d", len(fields), len(want))
+	}
+	for _, field := range fields {
+		if field.Type.String() != want[field.Name] {
+			t.Errorf("field %s resolved to %s, want %s", field.Name, field.Type, want[field.Name])
+		}
+	}
+
+	base, depth := fields[1].Type.Deref()
+	if depth != 2 || base.Kind != typeinfo.Struct || base.QualifiedName() != "time.Time" {
+		t.Errorf("Deref() = %s, %d; want time.Time, 2", base, depth)
+	}
+}
+
+func TestParser_NestedStructs(t *testing.T) {
+	filePath, cleanup := createTempFile(`
+package testdata
+
+type Audit struct {
+	CreatedBy string
+}
+
+type Node struct {
+	Audit
+	Value  int
+	Parent *Node
+}
+`)
+	defer cleanup()
+
+	cfg := &config.Config{InputPath: filePath, Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+	got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	node := got["Node"]
+	if len(node.Fields) != 3 {
+		t.Fatalf("Parse() got %d fields, want 3", len(node.Fields))
+	}
+	if !node.Fields[0].Embedded || node.Fields[0].Type != got["Audit"] {
+		t.Errorf("Embedded field not resolved to Audit: %+v", node.Fields[0])
+	}
+	if node.Fields[2].Type.Elem != node {
+		t.Errorf("Recursive field does not reference its own struct type")
+	}
+}
+
+func TestParser_BlankFieldTag(t *testing.T) {
+	filePath, cleanup := createTempFile(`
+package testdata
+
+type User struct {
+	_    struct{} ` + "`db:\"accounts\"`" + `
+	Name string
+}
+`)
+	defer cleanup()
+
+	cfg := &config.Config{InputPath: filePath, Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+	got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	user := got["User"]
+	if len(user.Fields) != 1 || user.Fields[0].Name != "Name" {
+		t.Errorf("Blank field should not be generated, got fields %+v", user.Fields)
+	}
+	if user.Tag.Get("db") != "accounts" {
+		t.Errorf("Struct tag = %q, want the tag of the blank field", user.Tag)
+	}
+}
+
+func TestParser_EnumValues(t *testing.T) {
+	dir, cleanup := createTempPackage(map[string]string{
+		"status.go": `
+package testdata
+
+type Status string
+
+const (
+	StatusActive  Status = "active"
+	StatusDeleted Status = "deleted"
+	statusDefault        = StatusActive
+	Unrelated            = "x"
+)
+`,
+		"account.go": `
+package testdata
+
+import "time"
+
+type Level uint8
+
+const (
+	LevelLow Level = iota + 1
+	LevelMid
+	LevelHigh
+)
+
+type Account struct {
+	Status  Status
+	Level   *Level
+	Timeout time.Duration
+	Name    string
+}
+`,
+	})
+	defer cleanup()
+
+	cfg := &config.Config{InputPath: filepath.Join(dir, "account.go"), Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+	got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	fields := got["Account"].Fields
+	if want := []string{"active", "deleted"}; !reflect.DeepEqual(fields[0].Type.Enum, want) {
+		t.Errorf("Status values = %v, want %v", fields[0].Type.Enum, want)
+	}
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(fields[1].Type.Elem.Enum, want) {
+		t.Errorf("Level values = %v, want %v", fields[1].Type.Elem.Enum, want)
+	}
+	if len(fields[2].Type.Enum) == 0 {
+		t.Errorf("Constants of imported packages are not detected")
+	}
+	if fields[3].Type.Enum != nil {
+		t.Errorf("Unnamed types should have no values, got %v", fields[3].Type.Enum)
+	}
+}
+
+func TestParser_RuneAlias(t *testing.T) {
+	dir, cleanup := createTempPackage(map[string]string{
+		"glyph.go": `
+package testdata
+
+type Glyph struct {
+	Char rune
+	Code int32
+	Data []byte
+}
+`,
+	})
+	defer cleanup()
+
+	cfg := &config.Config{InputPath: filepath.Join(dir, "glyph.go"), Fields: config.FieldsConfig{IgnoreStrategy: config.IncludeAll}}
+	got, err := NewParser(cfg, testutils.TestLogger()).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	fields := got["Glyph"].Fields
+	if fields[0].Type.Kind != typeinfo.Int32 || fields[0].Type.Name != "rune" {
+		t.Errorf("Char type = %+v, want the rune alias", fields[0].Type)
+	}
+	if fields[1].Type.Name != "" {
+		t.Errorf("Code type = %+v, want plain int32", fields[1].Type)
+	}
+	if fields[2].Type.Elem.Kind != typeinfo.Uint8 {
+		t.Errorf("Data element type = %+v, want uint8", fields[2].Type.Elem)
+	}
+}
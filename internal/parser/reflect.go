@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"reflect"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+var reflectKinds = map[reflect.Kind]typeinfo.Kind{
+	reflect.Bool:       typeinfo.Bool,
+	reflect.Int:        typeinfo.Int,
+	reflect.Int8:       typeinfo.Int8,
+	reflect.Int16:      typeinfo.Int16,
+	reflect.Int32:      typeinfo.Int32,
+	reflect.Int64:      typeinfo.Int64,
+	reflect.Uint:       typeinfo.Uint,
+	reflect.Uint8:      typeinfo.Uint8,
+	reflect.Uint16:     typeinfo.Uint16,
+	reflect.Uint32:     typeinfo.Uint32,
+	reflect.Uint64:     typeinfo.Uint64,
+	reflect.Uintptr:    typeinfo.Uintptr,
+	reflect.Float32:    typeinfo.Float32,
+	reflect.Float64:    typeinfo.Float64,
+	reflect.Complex64:  typeinfo.Complex64,
+	reflect.Complex128: typeinfo.Complex128,
+	reflect.String:     typeinfo.String,
+	reflect.Struct:     typeinfo.Struct,
+	reflect.Slice:      typeinfo.Slice,
+	reflect.Array:      typeinfo.Array,
+	reflect.Map:        typeinfo.Map,
+	reflect.Pointer:    typeinfo.Pointer,
+	reflect.Interface:  typeinfo.Interface,
+}
+
+// DescribeType converts a Go type obtained by reflection into a typeinfo.Type.
+// Struct fields are filtered and tagged by the same rules as for parsed packages.
+func (p *Parser) DescribeType(t reflect.Type) *typeinfo.Type {
+	if desc, ok := p.reflected[t]; ok {
+		return desc
+	}
+
+	desc := &typeinfo.Type{Kind: reflectKinds[t.Kind()], PkgPath: t.PkgPath(), Name: t.Name()}
+	if t.Name() != "" {
+		p.reflected[t] = desc // registered before recursing into fields
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		desc.Elem = p.DescribeType(t.Elem())
+	case reflect.Array:
+		desc.Elem = p.DescribeType(t.Elem())
+		desc.Len = int64(t.Len())
+	case reflect.Map:
+		desc.Key = p.DescribeType(t.Key())
+		desc.Elem = p.DescribeType(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Name == "_" {
+				desc.Tag = field.Tag
+				continue
+			}
+			mockTags := parseMockTags(field.Tag.Get("mock"))
+			if !p.shouldAddField(mockTags) {
+				continue
+			}
+			fieldType := p.DescribeType(field.Type)
+			mockTags = p.fieldTags(field.Name, fieldType, mockTags)
+			desc.Fields = append(desc.Fields, typeinfo.Field{
+				Name:     field.Name,
+				Type:     fieldType,
+				Tag:      field.Tag,
+				MockTags: mockTags,
+				Embedded: field.Anonymous,
+			})
+		}
+	}
+
+	return desc
+}
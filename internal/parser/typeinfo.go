@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"cmp"
+	"go/constant"
+	"go/types"
+	"slices"
+	"strconv"
+
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+var basicKinds = map[types.BasicKind]typeinfo.Kind{
+	types.Bool:       typeinfo.Bool,
+	types.Int:        typeinfo.Int,
+	types.Int8:       typeinfo.Int8,
+	types.Int16:      typeinfo.Int16,
+	types.Int32:      typeinfo.Int32,
+	types.Int64:      typeinfo.Int64,
+	types.Uint:       typeinfo.Uint,
+	types.Uint8:      typeinfo.Uint8,
+	types.Uint16:     typeinfo.Uint16,
+	types.Uint32:     typeinfo.Uint32,
+	types.Uint64:     typeinfo.Uint64,
+	types.Uintptr:    typeinfo.Uintptr,
+	types.Float32:    typeinfo.Float32,
+	types.Float64:    typeinfo.Float64,
+	types.Complex64:  typeinfo.Complex64,
+	types.Complex128: typeinfo.Complex128,
+	types.String:     typeinfo.String,
+}
+
+// describe converts a type-checked type into a typeinfo.Type.
+// Aliases are resolved, so that aliased imports and type aliases
+// produce the same descriptor as the original type.
+// Named types are described once, so self-referential types
+// like "type Node struct{ Parent *Node }" produce a cyclic descriptor graph.
+func (p *Parser) describe(t types.Type) *typeinfo.Type {
+	t = types.Unalias(t)
+
+	if named, ok := t.(*types.Named); ok {
+		key := types.TypeString(named, nil)
+		if desc, ok := p.described[key]; ok {
+			return desc
+		}
+
+		desc := &typeinfo.Type{}
+		p.described[key] = desc // registered before recursing into fields
+
+		*desc = *p.describe(named.Underlying())
+		obj := named.Obj()
+		desc.Name = obj.Name()
+		if obj.Pkg() != nil {
+			desc.PkgPath = obj.Pkg().Path()
+			desc.PkgName = obj.Pkg().Name()
+		}
+		if desc.Kind.IsBasic() && obj.Pkg() != nil {
+			desc.Enum = enumValues(named)
+		}
+		if args := named.TypeArgs(); args != nil {
+			desc.Args = make([]*typeinfo.Type, args.Len())
+			for i := 0; i < args.Len(); i++ {
+				desc.Args[i] = p.describe(args.At(i))
+			}
+		}
+		return desc
+	}
+
+	switch t := t.(type) {
+	case *types.Basic:
+		desc := &typeinfo.Type{Kind: basicKinds[t.Kind()]}
+		if t.Name() == "rune" {
+			// unlike reflection, go/types tells the rune alias from int32
+			desc.Name = t.Name()
+		}
+		return desc
+	case *types.Pointer:
+		return &typeinfo.Type{Kind: typeinfo.Pointer, Elem: p.describe(t.Elem())}
+	case *types.Slice:
+		return &typeinfo.Type{Kind: typeinfo.Slice, Elem: p.describe(t.Elem())}
+	case *types.Array:
+		return &typeinfo.Type{Kind: typeinfo.Array, Elem: p.describe(t.Elem()), Len: t.Len()}
+	case *types.Map:
+		return &typeinfo.Type{Kind: typeinfo.Map, Key: p.describe(t.Key()), Elem: p.describe(t.Elem())}
+	case *types.Struct:
+		desc := &typeinfo.Type{Kind: typeinfo.Struct}
+		desc.Fields, desc.Tag = p.extractFields(t)
+		return desc
+	case *types.Interface:
+		return &typeinfo.Type{Kind: typeinfo.Interface}
+	}
+	return &typeinfo.Type{Kind: typeinfo.Invalid}
+}
+
+// enumValues returns the distinct values of package level constants of a named type
+// in declaration order, e.g. the values of "const ( Active Status = iota; Deleted )".
+func enumValues(named *types.Named) []string {
+	scope := named.Obj().Pkg().Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(c.Type(), named) {
+			consts = append(consts, c)
+		}
+	}
+	slices.SortFunc(consts, func(a, b *types.Const) int { return cmp.Compare(a.Pos(), b.Pos()) })
+
+	var values []string
+	for _, c := range consts {
+		var value string
+		switch c.Val().Kind() {
+		case constant.String:
+			value = constant.StringVal(c.Val())
+		case constant.Float:
+			f, _ := constant.Float64Val(c.Val())
+			value = strconv.FormatFloat(f, 'g', -1, 64)
+		default:
+			value = c.Val().ExactString()
+		}
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,166 @@
+package typeinfo
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Kind is a category of a Go type
+type Kind int
+
+const (
+	Invalid Kind = iota // Unsupported type (channels, functions, unsafe pointers etc.)
+	Bool
+	Int
+	Int8
+	Int16
+	Int32
+	Int64
+	Uint
+	Uint8
+	Uint16
+	Uint32
+	Uint64
+	Uintptr
+	Float32
+	Float64
+	Complex64
+	Complex128
+	String
+	Struct
+	Slice
+	Array
+	Map
+	Pointer
+	Interface
+)
+
+var kindNames = map[Kind]string{
+	Invalid:    "invalid",
+	Bool:       "bool",
+	Int:        "int",
+	Int8:       "int8",
+	Int16:      "int16",
+	Int32:      "int32",
+	Int64:      "int64",
+	Uint:       "uint",
+	Uint8:      "uint8",
+	Uint16:     "uint16",
+	Uint32:     "uint32",
+	Uint64:     "uint64",
+	Uintptr:    "uintptr",
+	Float32:    "float32",
+	Float64:    "float64",
+	Complex64:  "complex64",
+	Complex128: "complex128",
+	String:     "string",
+	Struct:     "struct",
+	Slice:      "slice",
+	Array:      "array",
+	Map:        "map",
+	Pointer:    "pointer",
+	Interface:  "interface",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return "kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// IsBasic reports whether the kind is one of Go predeclared scalar types.
+func (k Kind) IsBasic() bool {
+	return k >= Bool && k <= String
+}
+
+// Type is a structured description of a Go type,
+// independent of the way it was obtained (go/types or reflect).
+type Type struct {
+	Kind    Kind
+	PkgPath string  // import path of a named type, empty for predeclared and unnamed types
+	PkgName string  // name of the package of a named type, if known
+	Name    string  // name of a named type, empty for unnamed types. "rune" for the rune alias of int32
+	Elem    *Type   // element type of slices, arrays and pointers, value type of maps
+	Key     *Type   // key type of maps
+	Len     int64   // length of arrays
+	Args    []*Type // type arguments of an instantiated generic type
+	Fields  []Field // fields of a struct
+	// Values of package level constants declared with a named basic type, like enum members
+	// "active" and "deleted" of "type Status string". Strings are unquoted, numbers are in decimal.
+	Enum []string
+	// Tag of the blank "_" field of a struct, if any. It holds options of the struct itself,
+	// e.g. `db:"users"` for the table name.
+	Tag reflect.StructTag
+}
+
+// Field is a single field of a struct type
+type Field struct {
+	Name     string
+	Type     *Type
+	Tag      reflect.StructTag // raw struct tag, e.g. `json:"id" mock:"min=1"`
+	MockTags map[string]string // parsed "mock" tags
+	Embedded bool              // true for embedded fields, like "Audit" in "struct{ Audit }"
+}
+
+// IsNamed reports whether the type is a defined (named) type, like time.Time.
+// Predeclared types like int are not considered named.
+func (t *Type) IsNamed() bool {
+	return t.Name != "" && t.PkgPath != ""
+}
+
+// QualifiedName returns the package path qualified name of a named type,
+// e.g. "github.com/google/uuid.UUID". For other types it is equal to String.
+func (t *Type) QualifiedName() string {
+	if !t.IsNamed() {
+		return t.String()
+	}
+	return t.PkgPath + "." + t.Name
+}
+
+// Deref strips all pointers from the type
+// and returns the pointed-to type with the pointer depth.
+func (t *Type) Deref() (*Type, int) {
+	depth := 0
+	for t.Kind == Pointer && t.Elem != nil {
+		t = t.Elem
+		depth++
+	}
+	return t, depth
+}
+
+// String returns a Go-like representation of the type, e.g. "map[string][]*time.Time".
+func (t *Type) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+
+	if t.IsNamed() {
+		name := t.QualifiedName()
+		if len(t.Args) == 0 {
+			return name
+		}
+		args := make([]string, len(t.Args))
+		for i, arg := range t.Args {
+			args[i] = arg.String()
+		}
+		return name + "[" + strings.Join(args, ",") + "]"
+	}
+
+	switch t.Kind {
+	case Pointer:
+		return "*" + t.Elem.String()
+	case Slice:
+		return "[]" + t.Elem.String()
+	case Array:
+		return "[" + strconv.FormatInt(t.Len, 10) + "]" + t.Elem.String()
+	case Map:
+		return "map[" + t.Key.String() + "]" + t.Elem.String()
+	case Struct:
+		return "struct{...}"
+	case Interface:
+		return "interface{...}"
+	}
+	return t.Kind.String()
+}
@@ -1,19 +1,56 @@
 package writer
 
 import (
+	"errors"
+	"io/fs"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/maksemen2/mockfactory/internal/config"
-	"github.com/maksemen2/mockfactory/internal/parser"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
 )
 
 // BaseWriter implements base writer functionality
 type BaseWriter struct {
-	structs map[string][]parser.StructField
+	structs map[string]*typeinfo.Type
 	config  *config.Config
 	logger  *slog.Logger
+	source  *generator.RandSource // random streams of the run
+	refs    *generator.RefPool    // values of fields referenced by "ref" tags
+	order   []string              // struct names, referenced structs first
+	cycle   error                 // cyclic references between structs, returned by EachRecord
+	created []string              // paths of the output files created by the run
+}
+
+// newBaseWriter creates a BaseWriter with a random source seeded with the configured seed,
+// or with the current time if no seed is provided.
+func newBaseWriter(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) BaseWriter {
+	seed := config.Generation.RandSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+		logger.Info("Seed not provided, using current time as seed", "seed", seed)
+	}
+	refs := generator.NewRefPool(structs)
+	order, err := refs.Order()
+	if err != nil {
+		logger.Error("Failed to order structs by references", "error", err)
+		order = sortedNames(structs)
+	}
+	return BaseWriter{
+		structs: structs,
+		config:  config,
+		logger:  logger,
+		source:  generator.NewRandSource(seed),
+		refs:    refs,
+		order:   order,
+		cycle:   err,
+	}
 }
 
 // GetFileName returns the file name for the struct
@@ -28,3 +65,110 @@ func (w *BaseWriter) GetFileName(structName string) string {
 	}
 	return structName
 }
+
+// filePath returns the path of the output file for the struct
+// in the FilePerStruct strategy, with the given extension (e.g. ".json").
+func (w *BaseWriter) filePath(structName, ext string) string {
+	fileName := w.GetFileName(structName)
+	if !strings.HasSuffix(fileName, ext) {
+		fileName += ext
+	}
+	return filepath.Join(w.config.Output.Path, fileName)
+}
+
+// create creates an output file and records its path, so that removeOnError removes it if the run fails.
+func (w *BaseWriter) create(path string) (*os.File, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w.created = append(w.created, path)
+	return file, nil
+}
+
+// removeOnError removes the output files created by the run if *err is not nil,
+// so that a failed run does not leave partial output. Writers defer it at the start of Write.
+func (w *BaseWriter) removeOnError(err *error) {
+	if *err == nil {
+		return
+	}
+	for _, path := range w.created {
+		if removeErr := os.Remove(path); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+			w.logger.Warn("Failed to remove partial output file", "path", path, "error", removeErr)
+			continue
+		}
+		w.logger.Info("Partial output file removed", "path", path)
+	}
+	w.created = nil
+}
+
+// NewBuilder creates a generator builder using the random source of the run.
+func (w *BaseWriter) NewBuilder() *generator.Builder {
+	options := generator.Options{
+		MaxDepth: w.config.Generation.MaxDepth,
+		Nullable: w.config.Generation.Nullable,
+		Locale:   w.config.Generation.Locale,
+		Now:      w.config.Generation.Now,
+		Refs:     w.refs,
+	}
+	return generator.NewBuilder(options, w.source, w.logger)
+}
+
+// GenerateRecords generates w.config.Generation.Count instances of the struct.
+func (w *BaseWriter) GenerateRecords(structName string, structType *typeinfo.Type) ([]*generator.Record, error) {
+	records := make([]*generator.Record, 0, w.config.Generation.Count)
+	err := w.EachRecord(structName, structType, func(record *generator.Record) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// EachRecord generates w.config.Generation.Count instances of the struct one by one
+// and passes them to fn, so that writers can stream instances without keeping them in memory.
+// Values of fields referenced by "ref" tags are collected for structs generated later.
+// Generation stops at the first error returned by fn.
+func (w *BaseWriter) EachRecord(structName string, structType *typeinfo.Type, fn func(*generator.Record) error) error {
+	if w.cycle != nil {
+		return w.cycle
+	}
+	structGenerator, err := w.NewBuilder().Build(structType, nil)
+	if err != nil {
+		w.logger.Error("Failed to get generator for struct", "structName", structName, "error", err)
+		return err
+	}
+
+	for i := 0; i < w.config.Generation.Count; i++ {
+		w.logger.Debug("Generating struct instance", "structName", structName, "instance", i+1)
+		value, err := structGenerator.EvaluateAny()
+		if err != nil {
+			w.logger.Error("Failed to evaluate generator for struct", "structName", structName, "error", err)
+			return err
+		}
+		record := value.(*generator.Record)
+		w.refs.Collect(structName, record)
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structNames returns names of the structs to write in a stable order,
+// where structs referenced by "ref" tags come before the structs referencing them.
+func (w *BaseWriter) structNames() []string {
+	return w.order
+}
+
+// sortedNames returns the names of the structs in alphabetical order.
+func sortedNames(structs map[string]*typeinfo.Type) []string {
+	names := make([]string, 0, len(structs))
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,49 @@
+package writer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestWriters_RemoveOutputOnError(t *testing.T) {
+	// "Account" is written first, "Badge" runs out of unique int8 values afterwards
+	structs := map[string]*typeinfo.Type{
+		"Account": {Kind: typeinfo.Struct, PkgPath: "example.com/models", PkgName: "models", Name: "Account", Fields: []typeinfo.Field{
+			{Name: "ID", Type: intType},
+		}},
+		"Badge": {Kind: typeinfo.Struct, PkgPath: "example.com/models", PkgName: "models", Name: "Badge", Fields: []typeinfo.Field{
+			{Name: "Code", Type: &typeinfo.Type{Kind: typeinfo.Int8}, MockTags: map[string]string{"unique": ""}},
+		}},
+	}
+
+	for format, factory := range WriterFactories {
+		for name, strategy := range map[string]config.OutputStrategy{"per-struct": config.FilePerStruct, "single-file": config.SingleFile} {
+			t.Run(format+"/"+name, func(t *testing.T) {
+				dir := t.TempDir()
+				path := dir
+				if strategy == config.SingleFile {
+					path = filepath.Join(dir, "mocks."+format)
+				}
+				cfg := &config.Config{
+					Generation: config.GenerationConfig{Count: 300, RandSeed: 1, MaxDepth: 5, Format: format},
+					Output:     config.OutputConfig{Path: path, OutputStrategy: strategy, Layout: config.KeyedLayout},
+					SQL:        config.SQLConfig{Dialect: "postgres", BatchSize: 100},
+				}
+				err := factory.Create(structs, cfg, testutils.TestLogger()).Write()
+				if !errors.Is(err, generator.ErrUniqueExhausted) {
+					t.Fatalf("Write() error = %v, want ErrUniqueExhausted", err)
+				}
+				if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+					t.Errorf("Partial output is not removed: %v", entries)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package writer
+
+import (
+	"slices"
+
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// column is a column of tabular output formats, like SQL or CSV.
+type column struct {
+	name string
+	path []string       // names of the fields leading to the value, through nested structs
+	typ  *typeinfo.Type // type of the field, used to detect conflicting CSV columns
+}
+
+// value returns the value of the column in a record, or nil if it is not generated.
+func (c column) value(record *generator.Record) any {
+	var value any = record
+	for _, name := range c.path {
+		record, ok := value.(*generator.Record)
+		if !ok {
+			return nil
+		}
+		value = nil
+		for _, field := range record.Fields {
+			if field.Field.Name == name {
+				value = field.Value
+				break
+			}
+		}
+	}
+	return value
+}
+
+// uniqueColumns removes columns with duplicate names.
+// As with Go field selectors, a column of the outer struct hides promoted columns with the same name.
+func uniqueColumns(all []column) []column {
+	var columns []column
+	for _, col := range all {
+		hidden := false
+		for _, other := range all {
+			if other.name == col.name && len(other.path) < len(col.path) {
+				hidden = true
+				break
+			}
+		}
+		if !hidden && !slices.ContainsFunc(columns, func(c column) bool { return c.name == col.name }) {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,213 @@
+package writer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// CsvWriter writes parsed structs as delimiter separated values:
+// a header row followed by a row per generated instance.
+type CsvWriter struct {
+	BaseWriter
+	comma rune // field delimiter, ',' for CSV and '\t' for TSV
+}
+
+func NewCsvWriter(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger, comma rune) Writer {
+	return &CsvWriter{BaseWriter: newBaseWriter(structs, config, logger), comma: comma}
+}
+
+// ext returns the extension of output files.
+func (w *CsvWriter) ext() string {
+	if w.comma == '\t' {
+		return ".tsv"
+	}
+	return ".csv"
+}
+
+// Write writes the parsed structs to CSV files.
+// A single file holds one table of all structs, with the struct name in the first "struct" column
+// and the union of columns of all structs. Cells of columns a struct does not have are empty.
+// Instances are written one by one, so memory usage does not depend on the count.
+func (w *CsvWriter) Write() (err error) {
+	switch w.config.CSV.Nested {
+	case "", "flatten", "json":
+	default:
+		w.logger.Error("Unsupported CSV output of nested structs", "nested", w.config.CSV.Nested)
+		return fmt.Errorf("unsupported CSV output of nested structs: %q, expected flatten or json", w.config.CSV.Nested)
+	}
+	defer w.removeOnError(&err)
+	if w.config.Output.OutputStrategy == config.SingleFile {
+		file, err := w.create(w.config.Output.Path)
+		if err != nil {
+			w.logger.Error("Failed to create single output file", "path", w.config.Output.Path, "error", err)
+			return err
+		}
+		w.logger.Info("Single output file created", "path", w.config.Output.Path)
+		defer file.Close()
+
+		if err := w.writeTable(file, w.structNames(), true, w.eachRecord); err != nil {
+			w.logger.Error("Failed to write CSV to file", "path", w.config.Output.Path, "error", err)
+			return err
+		}
+		w.logger.Info("Successfully wrote CSV output", "path", w.config.Output.Path)
+		return nil
+	}
+
+	for _, structName := range w.structNames() {
+		fileName := w.filePath(structName, w.ext())
+		w.logger.Debug("Creating output file for struct", "fileName", fileName)
+		file, err := w.create(fileName)
+		if err != nil {
+			w.logger.Error("Failed to create output file for struct", "structName", structName, "error", err)
+			return err
+		}
+		w.logger.Info("Output file created for struct", "structName", structName)
+		defer file.Close()
+
+		if err := w.writeTable(file, []string{structName}, false, w.eachRecord); err != nil {
+			w.logger.Error("Failed to write CSV to file", "structName", structName, "error", err)
+			return err
+		}
+		w.logger.Info("Successfully wrote CSV output for struct", "structName", structName)
+	}
+	return nil
+}
+
+// recordSource passes instances of a struct to fn.
+type recordSource func(structName string, fn func(*generator.Record) error) error
+
+func (w *CsvWriter) eachRecord(structName string, fn func(*generator.Record) error) error {
+	return w.EachRecord(structName, w.structs[structName], fn)
+}
+
+// writeTable writes a header row and a row per instance of the structs.
+// If withName is set, the first column holds the struct name of the row.
+func (w *CsvWriter) writeTable(out io.Writer, structNames []string, withName bool, records recordSource) error {
+	var header []string
+	if withName {
+		header = append(header, "struct")
+	}
+	columns := make([][]column, len(structNames))
+	types := make(map[string]string) // types of columns by name
+	conflicts := make(map[string]bool)
+	for i, structName := range structNames {
+		structType := w.structs[structName]
+		columns[i] = uniqueColumns(w.csvColumns(structType, "", nil, []*typeinfo.Type{structType}))
+		for _, col := range columns[i] {
+			typ, ok := types[col.name]
+			if !ok {
+				types[col.name] = col.typ.String()
+			} else if typ != col.typ.String() {
+				conflicts[col.name] = true
+			}
+		}
+	}
+
+	// columns of the same name and type are shared by structs,
+	// columns of the same name and different types are prefixed with the struct name
+	positions := make(map[string]int) // positions of columns in the header by name
+	for i, structName := range structNames {
+		for j, col := range columns[i] {
+			if conflicts[col.name] {
+				col.name = structName + "." + col.name
+				columns[i][j] = col
+			}
+			if _, ok := positions[col.name]; !ok {
+				positions[col.name] = len(header)
+				header = append(header, col.name)
+			}
+		}
+	}
+
+	writer := csv.NewWriter(out)
+	writer.Comma = w.comma
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]string, len(header))
+	for i, structName := range structNames {
+		err := records(structName, func(record *generator.Record) error {
+			clear(row)
+			if withName {
+				row[0] = structName
+			}
+			for _, col := range columns[i] {
+				row[positions[col.name]] = csvCell(col.value(record))
+			}
+			return writer.Write(row)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvColumns returns the columns of a struct in declaration order. Header names are taken from "csv" tags.
+// If the "flatten" nested output is configured, fields of nested structs become separate columns
+// with dotted names like "Address.City", fields of embedded structs are promoted without a prefix.
+// Otherwise, as well as for recursive and too deeply nested structs, the whole struct is a JSON cell.
+// visiting holds the structs from the root to t.
+func (w *CsvWriter) csvColumns(t *typeinfo.Type, prefix string, path []string, visiting []*typeinfo.Type) []column {
+	var columns []column
+	for _, field := range t.Fields {
+		tag := parseFieldTag(field, "csv")
+		if !isEncoded(field, tag) {
+			continue
+		}
+
+		name := tag.keyOf(field)
+		fieldPath := append(path[:len(path):len(path)], field.Name)
+		base, _ := field.Type.Deref()
+		_, generated := generator.LookupFactory(base)
+		flatten := w.config.CSV.Nested != "json" && base.Kind == typeinfo.Struct && !generated &&
+			len(visiting) <= w.config.Generation.MaxDepth && !slices.Contains(visiting, base)
+		if !flatten {
+			columns = append(columns, column{name: prefix + name, path: fieldPath, typ: field.Type})
+			continue
+		}
+
+		nestedPrefix := prefix + name + "."
+		if isPromoted(field, tag) {
+			nestedPrefix = prefix
+		}
+		columns = append(columns, w.csvColumns(base, nestedPrefix, fieldPath, append(visiting[:len(visiting):len(visiting)], base))...)
+	}
+	return columns
+}
+
+// csvCell formats a generated value as a cell. Nil values are written as empty cells,
+// nested structs, slices and maps as JSON.
+func csvCell(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case *generator.Record, []any, []generator.MapEntry:
+		encoded, err := json.Marshal(jsonValue(value))
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	}
+	return fmt.Sprint(value)
+}
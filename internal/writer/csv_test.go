@@ -0,0 +1,196 @@
+package writer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestCsvWriter_WriteTable(t *testing.T) {
+	address := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Address", Fields: []typeinfo.Field{
+		{Name: "City", Type: stringType},
+		{Name: "Zip", Type: intType, Tag: `csv:"zip_code"`},
+	}}
+	audit := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Audit", Fields: []typeinfo.Field{
+		{Name: "By", Type: stringType},
+	}}
+	user := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User"}
+	user.Fields = []typeinfo.Field{
+		{Name: "Name", Type: stringType, Tag: `csv:"name"`},
+		{Name: "Home", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: address}},
+		{Name: "Tags", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: stringType}},
+		{Name: "Secret", Type: stringType, Tag: `csv:"-"`},
+		{Name: "Audit", Type: audit, Embedded: true},
+		{Name: "Parent", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: user}},
+	}
+
+	records := []*generator.Record{
+		{Type: user, Fields: []generator.RecordField{
+			{Field: user.Fields[0], Value: "John, Jr."},
+			{Field: user.Fields[1], Value: &generator.Record{Type: address, Fields: []generator.RecordField{
+				{Field: address.Fields[0], Value: "Berlin"},
+				{Field: address.Fields[1], Value: 10115},
+			}}},
+			{Field: user.Fields[2], Value: []any{"a", "b"}},
+			{Field: user.Fields[3], Value: "secret"},
+			{Field: user.Fields[4], Value: &generator.Record{Type: audit, Fields: []generator.RecordField{
+				{Field: audit.Fields[0], Value: "admin"},
+			}}},
+			{Field: user.Fields[5], Value: nil},
+		}},
+		{Type: user, Fields: []generator.RecordField{
+			{Field: user.Fields[0], Value: "Jane"},
+			{Field: user.Fields[1], Value: nil},
+			{Field: user.Fields[2], Value: []any{}},
+			{Field: user.Fields[3], Value: "secret"},
+			{Field: user.Fields[4], Value: &generator.Record{Type: audit, Fields: []generator.RecordField{
+				{Field: audit.Fields[0], Value: "root"},
+			}}},
+			{Field: user.Fields[5], Value: nil},
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		nested string
+		comma  rune
+		want   string
+	}{
+		{
+			name:   "flatten",
+			nested: "flatten",
+			comma:  ',',
+			want: "name,Home.City,Home.zip_code,Tags,By,Parent\n" +
+				"\"John, Jr.\",Berlin,10115,\"[\"\"a\"\",\"\"b\"\"]\",admin,\n" +
+				"Jane,,,[],root,\n",
+		},
+		{
+			name:   "json",
+			nested: "json",
+			comma:  ',',
+			want: "name,Home,Tags,Audit,Parent\n" +
+				"\"John, Jr.\",\"{\"\"City\"\":\"\"Berlin\"\",\"\"Zip\"\":10115}\",\"[\"\"a\"\",\"\"b\"\"]\",\"{\"\"By\"\":\"\"admin\"\"}\",\n" +
+				"Jane,,[],\"{\"\"By\"\":\"\"root\"\"}\",\n",
+		},
+		{
+			name:   "tsv",
+			nested: "flatten",
+			comma:  '\t',
+			want: "name\tHome.City\tHome.zip_code\tTags\tBy\tParent\n" +
+				"John, Jr.\tBerlin\t10115\t\"[\"\"a\"\",\"\"b\"\"]\"\tadmin\t\n" +
+				"Jane\t\t\t[]\troot\t\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Generation: config.GenerationConfig{MaxDepth: 5},
+				CSV:        config.CSVConfig{Nested: tt.nested},
+			}
+			w := NewCsvWriter(map[string]*typeinfo.Type{"User": user}, cfg, testutils.TestLogger(), tt.comma).(*CsvWriter)
+
+			var got strings.Builder
+			if err := w.writeTable(&got, []string{"User"}, false, fixedRecords(map[string][]*generator.Record{"User": records})); err != nil {
+				t.Fatalf("writeTable() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("writeTable() =\n%s\nwant\n%s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCsvWriter_InvalidNested(t *testing.T) {
+	cfg := &config.Config{
+		Generation: config.GenerationConfig{Count: 1, MaxDepth: 5},
+		Output:     config.OutputConfig{Path: t.TempDir(), OutputStrategy: config.FilePerStruct},
+		CSV:        config.CSVConfig{Nested: "flat"},
+	}
+	user := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{{Name: "ID", Type: intType}}}
+	err := NewCsvWriter(map[string]*typeinfo.Type{"User": user}, cfg, testutils.TestLogger(), ',').Write()
+	if err == nil || !strings.Contains(err.Error(), "unsupported CSV output of nested structs") {
+		t.Errorf("Write() error = %v, want an unsupported nested output", err)
+	}
+}
+
+// fixedRecords returns a record source of prepared instances.
+func fixedRecords(records map[string][]*generator.Record) recordSource {
+	return func(structName string, fn func(*generator.Record) error) error {
+		for _, record := range records[structName] {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestCsvWriter_SingleTable(t *testing.T) {
+	user := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{
+		{Name: "ID", Type: intType},
+		{Name: "Name", Type: stringType},
+	}}
+	order := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Order", Fields: []typeinfo.Field{
+		{Name: "ID", Type: intType},
+		{Name: "Total", Type: intType},
+	}}
+	records := map[string][]*generator.Record{
+		"Order": {{Type: order, Fields: []generator.RecordField{{Field: order.Fields[0], Value: 1}, {Field: order.Fields[1], Value: 100}}}},
+		"User":  {{Type: user, Fields: []generator.RecordField{{Field: user.Fields[0], Value: 2}, {Field: user.Fields[1], Value: "John"}}}},
+	}
+
+	cfg := &config.Config{Generation: config.GenerationConfig{MaxDepth: 5}}
+	w := NewCsvWriter(map[string]*typeinfo.Type{"User": user, "Order": order}, cfg, testutils.TestLogger(), ',').(*CsvWriter)
+
+	var got strings.Builder
+	if err := w.writeTable(&got, w.structNames(), true, fixedRecords(records)); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+	want := "struct,ID,Total,Name\n" +
+		"Order,1,100,\n" +
+		"User,2,,John\n"
+	if got.String() != want {
+		t.Errorf("writeTable() =\n%s\nwant\n%s", got.String(), want)
+	}
+}
+
+func TestCsvWriter_SingleTableConflictingColumns(t *testing.T) {
+	// ID and Name are shared, Code has different types and gets a column per struct
+	user := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{
+		{Name: "ID", Type: intType},
+		{Name: "Name", Type: stringType},
+		{Name: "Code", Type: intType},
+	}}
+	product := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Product", Fields: []typeinfo.Field{
+		{Name: "ID", Type: intType},
+		{Name: "Name", Type: stringType},
+		{Name: "Code", Type: stringType},
+	}}
+	records := map[string][]*generator.Record{
+		"Product": {{Type: product, Fields: []generator.RecordField{
+			{Field: product.Fields[0], Value: 1}, {Field: product.Fields[1], Value: "Phone"}, {Field: product.Fields[2], Value: "P-1"},
+		}}},
+		"User": {{Type: user, Fields: []generator.RecordField{
+			{Field: user.Fields[0], Value: 2}, {Field: user.Fields[1], Value: "John"}, {Field: user.Fields[2], Value: 7},
+		}}},
+	}
+
+	cfg := &config.Config{Generation: config.GenerationConfig{MaxDepth: 5}}
+	w := NewCsvWriter(map[string]*typeinfo.Type{"User": user, "Product": product}, cfg, testutils.TestLogger(), ',').(*CsvWriter)
+
+	var got strings.Builder
+	if err := w.writeTable(&got, w.structNames(), true, fixedRecords(records)); err != nil {
+		t.Fatalf("writeTable() error = %v", err)
+	}
+	want := "struct,ID,Name,Product.Code,User.Code\n" +
+		"Product,1,Phone,P-1,\n" +
+		"User,2,John,,7\n"
+	if got.String() != want {
+		t.Errorf("writeTable() =\n%s\nwant\n%s", got.String(), want)
+	}
+}
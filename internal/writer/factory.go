@@ -4,22 +4,74 @@ import (
 	"log/slog"
 
 	"github.com/maksemen2/mockfactory/internal/config"
-	"github.com/maksemen2/mockfactory/internal/parser"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
 )
 
 // WriterFactory defines an interface
 // for creating Writer instances using parsed struct fields.
 type WriterFactory interface {
-	Create(structs map[string][]parser.StructField, config *config.Config, logger *slog.Logger) Writer
+	Create(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer
 }
 
 var WriterFactories = map[string]WriterFactory{
-	"json": &JsonWriterFactory{},
+	"json":   &JsonWriterFactory{},
+	"ndjson": &NdjsonWriterFactory{},
+	"go":     &GoWriterFactory{},
+	"sql":    &SqlWriterFactory{},
+	"csv":    &CsvWriterFactory{Comma: ','},
+	"tsv":    &CsvWriterFactory{Comma: '\t'},
+	"yaml":   &YamlWriterFactory{},
+	"toml":   &TomlWriterFactory{},
 }
 
 type JsonWriterFactory struct{}
 
 // Create instantiates a new JSON Writer using the provided struct definitions.
-func (f *JsonWriterFactory) Create(structs map[string][]parser.StructField, config *config.Config, logger *slog.Logger) Writer {
+func (f *JsonWriterFactory) Create(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
 	return NewJsonWriter(structs, config, logger)
 }
+
+type NdjsonWriterFactory struct{}
+
+// Create instantiates a new NDJSON Writer using the provided struct definitions.
+func (f *NdjsonWriterFactory) Create(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return NewNdjsonWriter(structs, config, logger)
+}
+
+type GoWriterFactory struct{}
+
+// Create instantiates a new Go source Writer using the provided struct definitions.
+func (f *GoWriterFactory) Create(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return NewGoWriter(structs, config, logger)
+}
+
+type SqlWriterFactory struct{}
+
+// Create instantiates a new SQL Writer using the provided struct definitions.
+func (f *SqlWriterFactory) Create(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return NewSqlWriter(structs, config, logger)
+}
+
+// CsvWriterFactory creates writers of delimiter separated values, like CSV or TSV.
+type CsvWriterFactory struct {
+	Comma rune // field delimiter
+}
+
+// Create instantiates a new CSV Writer using the provided struct definitions.
+func (f *CsvWriterFactory) Create(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return NewCsvWriter(structs, config, logger, f.Comma)
+}
+
+type YamlWriterFactory struct{}
+
+// Create instantiates a new YAML Writer using the provided struct definitions.
+func (f *YamlWriterFactory) Create(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return NewYamlWriter(structs, config, logger)
+}
+
+type TomlWriterFactory struct{}
+
+// Create instantiates a new TOML Writer using the provided struct definitions.
+func (f *TomlWriterFactory) Create(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return NewTomlWriter(structs, config, logger)
+}
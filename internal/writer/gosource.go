@@ -0,0 +1,386 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// GoWriter writes parsed structs as Go source files
+// with fixture literals like "var MockUsers = []User{...}",
+// declared in the package of the structs.
+type GoWriter struct {
+	BaseWriter
+}
+
+func NewGoWriter(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return &GoWriter{BaseWriter: newBaseWriter(structs, config, logger)}
+}
+
+// Write writes the parsed structs to Go source files.
+func (w *GoWriter) Write() (err error) {
+	defer w.removeOnError(&err)
+	if w.config.Output.OutputStrategy == config.SingleFile {
+		var file *goFile
+		for _, structName := range w.structNames() {
+			structType := w.structs[structName]
+			if file == nil {
+				file = newGoFile(structType)
+			} else if file.pkgPath != structType.PkgPath {
+				w.logger.Error("Structs from different packages can not be written to a single Go file",
+					"structName", structName, "package", structType.PkgPath, "filePackage", file.pkgPath)
+				return fmt.Errorf("struct %s is declared in package %s, but the file is in package %s", structName, structType.PkgPath, file.pkgPath)
+			}
+			if err := w.addStruct(file, structName, structType); err != nil {
+				return err
+			}
+		}
+		if file == nil {
+			return nil
+		}
+		return w.writeFile(file, w.config.Output.Path)
+	}
+
+	for _, structName := range w.structNames() {
+		structType := w.structs[structName]
+		file := newGoFile(structType)
+		if err := w.addStruct(file, structName, structType); err != nil {
+			return err
+		}
+		if err := w.writeFile(file, w.filePath(structName, ".go")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *GoWriter) addStruct(file *goFile, structName string, structType *typeinfo.Type) error {
+	records, err := w.GenerateRecords(structName, structType)
+	if err != nil {
+		return err
+	}
+	if err := file.addVar(structType, records); err != nil {
+		w.logger.Error("Failed to write Go literals", "structName", structName, "error", err)
+		return err
+	}
+	w.logger.Debug("Go literals written for struct", "structName", structName)
+	return nil
+}
+
+func (w *GoWriter) writeFile(file *goFile, fileName string) error {
+	source, err := file.bytes()
+	if err != nil {
+		w.logger.Error("Failed to format Go source", "fileName", fileName, "error", err)
+		return err
+	}
+	out, err := w.create(fileName)
+	if err != nil {
+		w.logger.Error("Failed to create Go source file", "fileName", fileName, "error", err)
+		return err
+	}
+	defer out.Close()
+	if _, err := out.Write(source); err != nil {
+		w.logger.Error("Failed to write Go source file", "fileName", fileName, "error", err)
+		return err
+	}
+	w.logger.Info("Successfully wrote Go output", "fileName", fileName)
+	return nil
+}
+
+// goFile accumulates declarations of a single Go source file.
+type goFile struct {
+	pkgPath string
+	pkgName string
+	imports map[string]string // import path -> package name
+	body    bytes.Buffer
+}
+
+func newGoFile(structType *typeinfo.Type) *goFile {
+	return &goFile{
+		pkgPath: structType.PkgPath,
+		pkgName: packageName(structType),
+		imports: make(map[string]string),
+	}
+}
+
+// addVar declares a variable with generated instances of the struct.
+func (f *goFile) addVar(structType *typeinfo.Type, records []*generator.Record) error {
+	fmt.Fprintf(&f.body, "\nvar Mock%s = []%s{\n", plural(structType.Name), f.typeExpr(structType))
+	for _, record := range records {
+		literal, err := f.literal(structType, record)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&f.body, "%s,\n", literal)
+	}
+	f.body.WriteString("}\n")
+	return nil
+}
+
+// bytes returns the formatted source of the file.
+func (f *goFile) bytes() ([]byte, error) {
+	var src bytes.Buffer
+	src.WriteString("// Code generated by mockfactory. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n", f.pkgName)
+
+	if len(f.imports) > 0 {
+		paths := make([]string, 0, len(f.imports))
+		for importPath := range f.imports {
+			paths = append(paths, importPath)
+		}
+		sort.Strings(paths)
+
+		src.WriteString("\nimport (\n")
+		for _, importPath := range paths {
+			name := f.imports[importPath]
+			if name == path.Base(importPath) {
+				fmt.Fprintf(&src, "%q\n", importPath)
+			} else {
+				fmt.Fprintf(&src, "%s %q\n", name, importPath)
+			}
+		}
+		src.WriteString(")\n")
+	}
+
+	src.Write(f.body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// qualifier returns the name a package is referred to in the file, importing it if needed.
+// It returns an empty string for the package of the file itself.
+func (f *goFile) qualifier(pkgPath, pkgName string) string {
+	if pkgPath == f.pkgPath {
+		return ""
+	}
+	if name, ok := f.imports[pkgPath]; ok {
+		return name
+	}
+
+	if pkgName == "" {
+		pkgName = path.Base(pkgPath)
+	}
+	name := pkgName
+	for i := 2; f.isImportName(name); i++ {
+		name = pkgName + strconv.Itoa(i)
+	}
+	f.imports[pkgPath] = name
+	return name
+}
+
+func (f *goFile) isImportName(name string) bool {
+	for _, imported := range f.imports {
+		if imported == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeExpr returns the Go expression of the type.
+func (f *goFile) typeExpr(t *typeinfo.Type) string {
+	if t.IsNamed() {
+		name := t.Name
+		if qualifier := f.qualifier(t.PkgPath, t.PkgName); qualifier != "" {
+			name = qualifier + "." + name
+		}
+		if len(t.Args) > 0 {
+			args := make([]string, len(t.Args))
+			for i, arg := range t.Args {
+				args[i] = f.typeExpr(arg)
+			}
+			name += "[" + strings.Join(args, ", ") + "]"
+		}
+		return name
+	}
+
+	switch t.Kind {
+	case typeinfo.Pointer:
+		return "*" + f.typeExpr(t.Elem)
+	case typeinfo.Slice:
+		return "[]" + f.typeExpr(t.Elem)
+	case typeinfo.Array:
+		return "[" + strconv.FormatInt(t.Len, 10) + "]" + f.typeExpr(t.Elem)
+	case typeinfo.Map:
+		return "map[" + f.typeExpr(t.Key) + "]" + f.typeExpr(t.Elem)
+	case typeinfo.Interface:
+		return "any"
+	case typeinfo.Struct:
+		// anonymous struct, only fields known to the parser can be declared
+		var fields strings.Builder
+		fields.WriteString("struct {\n")
+		for _, field := range t.Fields {
+			if field.Embedded {
+				fields.WriteString(f.typeExpr(field.Type))
+			} else {
+				fields.WriteString(field.Name + " " + f.typeExpr(field.Type))
+			}
+			if field.Tag != "" {
+				fields.WriteString(" " + strconv.Quote(string(field.Tag)))
+			}
+			fields.WriteString("\n")
+		}
+		fields.WriteString("}")
+		return fields.String()
+	}
+	return t.Kind.String()
+}
+
+// literal returns a Go expression of a generated value of the given type.
+func (f *goFile) literal(t *typeinfo.Type, value any) (string, error) {
+	switch t.Kind {
+	case typeinfo.Pointer:
+		if value == nil {
+			return "nil", nil
+		}
+		elem, err := f.literal(t.Elem, value)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := value.(*generator.Record); ok && t.Elem.Kind == typeinfo.Struct {
+			return "&" + elem, nil
+		}
+		// addresses of other values can only be taken through a variable
+		return fmt.Sprintf("func() %s { var v %s = %s; return &v }()", f.typeExpr(t), f.typeExpr(t.Elem), elem), nil
+
+	case typeinfo.Struct:
+		if value == nil {
+			return f.typeExpr(t) + "{}", nil
+		}
+		if record, ok := value.(*generator.Record); ok {
+			return f.structLiteral(t, record)
+		}
+
+	case typeinfo.Slice, typeinfo.Array:
+		if value == nil && t.Kind == typeinfo.Array {
+			return f.typeExpr(t) + "{}", nil
+		}
+		if value == nil {
+			return "nil", nil
+		}
+		if bytes, ok := value.(generator.Bytes); ok {
+			lits := make([]string, len(bytes.Data))
+			for i, b := range bytes.Data {
+				lits[i] = fmt.Sprintf("0x%02x", b)
+			}
+			return f.typeExpr(t) + "{" + strings.Join(lits, ", ") + "}", nil
+		}
+		if elems, ok := value.([]any); ok {
+			lits := make([]string, len(elems))
+			for i, elem := range elems {
+				lit, err := f.literal(t.Elem, elem)
+				if err != nil {
+					return "", err
+				}
+				lits[i] = lit
+			}
+			return f.typeExpr(t) + "{" + strings.Join(lits, ", ") + "}", nil
+		}
+
+	case typeinfo.Map:
+		if value == nil {
+			return "nil", nil
+		}
+		if entries, ok := value.([]generator.MapEntry); ok {
+			lits := make([]string, len(entries))
+			for i, entry := range entries {
+				key, err := f.literal(t.Key, entry.Key)
+				if err != nil {
+					return "", err
+				}
+				elem, err := f.literal(t.Elem, entry.Value)
+				if err != nil {
+					return "", err
+				}
+				lits[i] = key + ": " + elem
+			}
+			return f.typeExpr(t) + "{" + strings.Join(lits, ", ") + "}", nil
+		}
+	}
+
+	return f.scalarLiteral(value)
+}
+
+func (f *goFile) structLiteral(t *typeinfo.Type, record *generator.Record) (string, error) {
+	var lit strings.Builder
+	lit.WriteString(f.typeExpr(t) + "{\n")
+	for _, field := range record.Fields {
+		if field.Value == nil {
+			continue // zero value
+		}
+		if t.PkgPath != f.pkgPath && !token.IsExported(field.Field.Name) {
+			continue // unexported fields of other packages can not be set
+		}
+		value, err := f.literal(field.Field.Type, field.Value)
+		if err != nil {
+			return "", fmt.Errorf("field %s: %w", field.Field.Name, err)
+		}
+		lit.WriteString(field.Field.Name + ": " + value + ",\n")
+	}
+	lit.WriteString("}")
+	return lit.String(), nil
+}
+
+// scalarLiteral returns a Go expression of a predeclared or registered type value.
+// Untyped constants are used for predeclared types, so they are assignable to named types too.
+func (f *goFile) scalarLiteral(value any) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr:
+		return fmt.Sprint(value), nil
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64), nil
+	case complex64, complex128:
+		return fmt.Sprint(value), nil
+	case time.Time:
+		t := value.UTC()
+		pkg := f.qualifier("time", "time")
+		return fmt.Sprintf("%s.Date(%d, %s.%s, %d, %d, %d, %d, %d, %s.UTC)",
+			pkg, t.Year(), pkg, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), pkg), nil
+	case time.Duration:
+		return fmt.Sprintf("%s.Duration(%d)", f.qualifier("time", "time"), int64(value)), nil
+	case uuid.UUID:
+		return fmt.Sprintf("%s.MustParse(%q)", f.qualifier("github.com/google/uuid", "uuid"), value.String()), nil
+	}
+	return "", fmt.Errorf("%w: %T", errUnsupportedLiteral, value)
+}
+
+var errUnsupportedLiteral = errors.New("can not write Go literal of value")
+
+// packageName returns the name of the package a struct is declared in.
+func packageName(t *typeinfo.Type) string {
+	if t.PkgName != "" {
+		return t.PkgName
+	}
+	return path.Base(t.PkgPath)
+}
+
+// plural returns a simple English plural of a struct name, e.g. "User" -> "Users".
+func plural(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	case len(name) > 1 && strings.HasSuffix(lower, "y") && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
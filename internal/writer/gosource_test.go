@@ -0,0 +1,150 @@
+package writer
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestGoFile_TypeChecks(t *testing.T) {
+	const pkgPath = "example.com/models"
+	timeType := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "time", PkgName: "time", Name: "Time"}
+	address := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: pkgPath, PkgName: "models", Name: "Address", Fields: []typeinfo.Field{
+		{Name: "City", Type: stringType},
+	}}
+	addressPtr := &typeinfo.Type{Kind: typeinfo.Pointer, Elem: address}
+	intPtr := &typeinfo.Type{Kind: typeinfo.Pointer, Elem: intType}
+	company := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: pkgPath, PkgName: "models", Name: "Company"}
+	user := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: pkgPath, PkgName: "models", Name: "User", Fields: []typeinfo.Field{
+		{Name: "ID", Type: intType},
+		{Name: "Score", Type: &typeinfo.Type{Kind: typeinfo.Float32}},
+		{Name: "Age", Type: intPtr},
+		{Name: "Manager", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: intPtr}},
+		{Name: "Home", Type: address},
+		{Name: "Work", Type: addressPtr},
+		{Name: "Tags", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: stringType}},
+		{Name: "Codes", Type: &typeinfo.Type{Kind: typeinfo.Array, Len: 2, Elem: intType}},
+		{Name: "Labels", Type: &typeinfo.Type{Kind: typeinfo.Map, Key: stringType, Elem: addressPtr}},
+		{Name: "CreatedAt", Type: timeType},
+		{Name: "Employer", Type: company},
+		{Name: "Avatar", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: &typeinfo.Type{Kind: typeinfo.Uint8}}},
+		{Name: "Hash", Type: &typeinfo.Type{Kind: typeinfo.Array, Len: 2, Elem: &typeinfo.Type{Kind: typeinfo.Uint8}}},
+		{Name: "Phase", Type: &typeinfo.Type{Kind: typeinfo.Complex128}},
+		{Name: "Timeout", Type: &typeinfo.Type{Kind: typeinfo.Int64, PkgPath: "time", PkgName: "time", Name: "Duration"}},
+	}}
+
+	created := time.Date(2024, time.March, 1, 12, 30, 0, 500, time.UTC)
+	home := &generator.Record{Type: address, Fields: []generator.RecordField{{Field: address.Fields[0], Value: "Berlin"}}}
+	record := &generator.Record{Type: user, Fields: []generator.RecordField{
+		{Field: user.Fields[0], Value: -7},
+		{Field: user.Fields[1], Value: float32(1.5)},
+		{Field: user.Fields[2], Value: 30},
+		{Field: user.Fields[3], Value: 5},
+		{Field: user.Fields[4], Value: home},
+		{Field: user.Fields[5], Value: home},
+		{Field: user.Fields[6], Value: []any{"a", "b\"c"}},
+		{Field: user.Fields[7], Value: []any{1, 2}},
+		{Field: user.Fields[8], Value: []generator.MapEntry{{Key: "x", Value: home}, {Key: "y", Value: nil}}},
+		{Field: user.Fields[9], Value: created},
+		{Field: user.Fields[10], Value: nil}, // cut by the depth limit
+		{Field: user.Fields[11], Value: generator.Bytes{Data: []byte{1, 0xff}, Encoding: "base64"}},
+		{Field: user.Fields[12], Value: generator.Bytes{Data: []byte{2, 3}, Encoding: "hex"}},
+		{Field: user.Fields[13], Value: complex(1.5, -2)},
+		{Field: user.Fields[14], Value: 90 * time.Second},
+	}}
+
+	file := newGoFile(user)
+	if err := file.addVar(user, []*generator.Record{record}); err != nil {
+		t.Fatalf("addVar() error = %v", err)
+	}
+	source, err := file.bytes()
+	if err != nil {
+		t.Fatalf("bytes() error = %v", err)
+	}
+
+	compact := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"package models",
+		`"time"`,
+		"var MockUsers = []User{",
+		"ID: -7,",
+		"Home: Address{ City: \"Berlin\", },",
+		"Work: &Address{",
+		`Tags: []string{"a", "b\"c"}`,
+		"Codes: [2]int{1, 2}",
+		"time.Date(2024, time.March, 1, 12, 30, 0, 500, time.UTC)",
+		"Avatar: []uint8{0x01, 0xff}",
+		"Hash: [2]uint8{0x02, 0x03}",
+		"Phase: (1.5 - 2i)",
+		"Timeout: time.Duration(90000000000)",
+	} {
+		if !strings.Contains(compact, want) {
+			t.Errorf("Generated source does not contain %q:\n%s", want, source)
+		}
+	}
+	if strings.Contains(compact, "Employer") {
+		t.Errorf("Fields without values should be omitted:\n%s", source)
+	}
+
+	const models = `package models
+
+import "time"
+
+type Address struct{ City string }
+type Company struct{ Name string }
+type User struct {
+	ID        int
+	Score     float32
+	Age       *int
+	Manager   **int
+	Home      Address
+	Work      *Address
+	Tags      []string
+	Codes     [2]int
+	Labels    map[string]*Address
+	CreatedAt time.Time
+	Employer  Company
+	Avatar    []byte
+	Hash      [2]byte
+	Phase     complex128
+	Timeout   time.Duration
+}
+`
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{"models.go": models, "mocks.go": string(source)} {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v\n%s", name, err, src)
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check(pkgPath, fset, files, nil); err != nil {
+		t.Errorf("Generated source does not type check: %v\n%s", err, source)
+	}
+}
+
+func TestPlural(t *testing.T) {
+	tests := map[string]string{
+		"User":    "Users",
+		"Address": "Addresses",
+		"Box":     "Boxes",
+		"Match":   "Matches",
+		"Company": "Companies",
+		"Key":     "Keys",
+	}
+	for name, want := range tests {
+		if got := plural(name); got != want {
+			t.Errorf("plural(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -1,14 +1,16 @@
 package writer
 
 import (
+	"bufio"
+	"bytes"
+	"encoding"
 	"encoding/json"
+	"fmt"
 	"log/slog"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/maksemen2/mockfactory/internal/config"
-	"github.com/maksemen2/mockfactory/internal/parser"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
 )
 
 // JsonWriter writes parsed structs in a json format
@@ -16,78 +18,258 @@ type JsonWriter struct {
 	BaseWriter
 }
 
-func NewJsonWriter(structs map[string][]parser.StructField, config *config.Config, logger *slog.Logger) Writer {
-	return &JsonWriter{BaseWriter: BaseWriter{
-		structs: structs,
-		config:  config,
-		logger:  logger,
-	}}
+func NewJsonWriter(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return &JsonWriter{BaseWriter: newBaseWriter(structs, config, logger)}
 }
 
-// Write writes the parsed structs to a JSON file.
-func (w *JsonWriter) Write() error {
-	var file *os.File
-	var err error
-
+// Write writes the parsed structs to JSON files.
+// Instances are encoded one by one, so memory usage does not depend on the count.
+func (w *JsonWriter) Write() (err error) {
+	defer w.removeOnError(&err)
 	if w.config.Output.OutputStrategy == config.SingleFile {
-		file, err = os.Create(w.config.Output.Path)
+		return w.writeSingleFile()
+	}
+
+	for _, structName := range w.structNames() {
+		fileName := w.filePath(structName, ".json")
+		w.logger.Debug("Creating output file for struct", "fileName", fileName)
+		file, err := w.create(fileName)
 		if err != nil {
-			w.logger.Error("Failed to create single output file", "path", w.config.Output.Path, "error", err)
+			w.logger.Error("Failed to create output file for struct", "structName", structName, "error", err)
 			return err
 		}
-		w.logger.Info("Single output file created", "path", w.config.Output.Path)
+		w.logger.Info("Output file created for struct", "structName", structName)
 		defer file.Close()
+
+		out := bufio.NewWriter(file)
+		if err := w.writeArray(out, "", structName, w.structs[structName]); err != nil {
+			return err
+		}
+		if err := out.Flush(); err != nil {
+			w.logger.Error("Failed to write JSON to file", "structName", structName, "error", err)
+			return err
+		}
+		w.logger.Info("Successfully wrote JSON output for struct", "structName", structName)
 	}
+	return nil
+}
 
-	for structName, fields := range w.structs {
-		if w.config.Output.OutputStrategy == config.FilePerStruct {
-			fileName := w.GetFileName(structName)
-			if !strings.HasSuffix(fileName, ".json") {
-				fileName += ".json"
-			}
-			w.logger.Debug("Creating output file for struct", "fileName", fileName)
-			file, err = os.Create(filepath.Join(w.config.Output.Path, w.GetFileName(structName)+".json"))
-			if err != nil {
-				w.logger.Error("Failed to create output file for struct", "structName", structName, "error", err)
-				return err
-			}
-			w.logger.Info("Output file created for struct", "structName", structName)
-			defer file.Close()
-		}
-
-		// writing each struct w.config.Generation.Count times
-		count := w.config.Generation.Count
-		structs := make([]map[string]any, count)
-		for i := 0; i < count; i++ {
-			// write the struct to the file
-			toWrite := make(map[string]any)
-			w.logger.Debug("Writing struct instance", "structName", structName, "instance", i+1)
-			for _, field := range fields {
-				generator, err := field.ToGenerator(w.config.Generation.RandSeed, w.logger)
-				if err != nil {
-					w.logger.Error("Failed to get generator for field", "fieldName", field.Name, "error", err)
-					return err
-				}
-				fieldValue, err := generator.EvaluateAny()
-				if err != nil {
-					w.logger.Error("Failed to evaluate generator for field", "fieldName", field.Name, "error", err)
-					return err
-				}
-				toWrite[field.Name] = fieldValue
-			}
-			structs[i] = toWrite
-		}
-		jsonStruct, err := json.MarshalIndent(structs, "", " ")
+// writeSingleFile writes all structs to a single JSON document.
+// The "keyed" layout is an object of instance arrays keyed by struct names: {"User": [...]},
+// the "list" layout is an array of objects with struct names and instances: [{"name": "User", "items": [...]}].
+func (w *JsonWriter) writeSingleFile() error {
+	file, err := w.create(w.config.Output.Path)
+	if err != nil {
+		w.logger.Error("Failed to create single output file", "path", w.config.Output.Path, "error", err)
+		return err
+	}
+	w.logger.Info("Single output file created", "path", w.config.Output.Path)
+	defer file.Close()
+
+	out := bufio.NewWriter(file)
+	keyed := w.config.Output.Layout != config.ListLayout
+	if keyed {
+		out.WriteString("{")
+	} else {
+		out.WriteString("[")
+	}
+	for i, structName := range w.structNames() {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		w.newline(out, " ")
+		name, _ := json.Marshal(structName)
+
+		if keyed {
+			out.Write(name)
+			out.WriteString(w.colon())
+			err = w.writeArray(out, " ", structName, w.structs[structName])
+		} else {
+			out.WriteString("{")
+			w.newline(out, "  ")
+			out.WriteString(`"name"` + w.colon())
+			out.Write(name)
+			out.WriteString(",")
+			w.newline(out, "  ")
+			out.WriteString(`"items"` + w.colon())
+			err = w.writeArray(out, "  ", structName, w.structs[structName])
+			w.newline(out, " ")
+			out.WriteString("}")
+		}
 		if err != nil {
-			w.logger.Error("Failed to marshal JSON", "structName", structName, "error", err)
 			return err
 		}
-		w.logger.Debug("Writing JSON data to file", "structName", structName)
-		if _, err := file.Write(jsonStruct); err != nil {
-			w.logger.Error("Failed to write JSON to file", "structName", structName, "error", err, "json", jsonStruct)
+		w.logger.Info("Successfully wrote JSON output for struct", "structName", structName)
+	}
+	if len(w.structs) > 0 {
+		w.newline(out, "")
+	}
+	if keyed {
+		out.WriteString("}")
+	} else {
+		out.WriteString("]")
+	}
+
+	if err := out.Flush(); err != nil {
+		w.logger.Error("Failed to write JSON to file", "path", w.config.Output.Path, "error", err)
+		return err
+	}
+	return nil
+}
+
+// writeArray streams the instances of a struct as a JSON array, starting at the given indentation.
+// The indented output is the same as json.MarshalIndent produces for the array nested at that level.
+// Encoding and write errors are logged here, generation errors are logged by EachRecord.
+func (w *JsonWriter) writeArray(out *bufio.Writer, indent string, structName string, structType *typeinfo.Type) error {
+	out.WriteString("[")
+	empty := true
+	err := w.EachRecord(structName, structType, func(record *generator.Record) error {
+		var encoded []byte
+		var err error
+		if w.config.JSON.Compact {
+			encoded, err = json.Marshal(recordToObject(record))
+		} else {
+			encoded, err = json.MarshalIndent(recordToObject(record), indent+" ", " ")
+		}
+		if err != nil {
+			w.logger.Error("Failed to encode JSON", "structName", structName, "error", err)
 			return err
 		}
-		w.logger.Info("Successfully wrote JSON output for struct", "structName", structName)
+
+		if !empty {
+			out.WriteString(",")
+		}
+		w.newline(out, indent+" ")
+		empty = false
+		if _, err := out.Write(encoded); err != nil {
+			w.logger.Error("Failed to write JSON to file", "structName", structName, "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !empty {
+		w.newline(out, indent)
+	}
+	if _, err := out.WriteString("]"); err != nil {
+		w.logger.Error("Failed to write JSON to file", "structName", structName, "error", err)
+		return err
 	}
 	return nil
 }
+
+// newline starts a new indented line, unless compact JSON is configured.
+func (w *JsonWriter) newline(out *bufio.Writer, indent string) {
+	if !w.config.JSON.Compact {
+		out.WriteString("\n" + indent)
+	}
+}
+
+// colon returns the separator of object keys and values.
+func (w *JsonWriter) colon() string {
+	if w.config.JSON.Compact {
+		return ":"
+	}
+	return ": "
+}
+
+// jsonEncoding names and promotes fields the same way encoding/json does:
+// keys are taken from "json" tags, fields of embedded structs are promoted to the outer object.
+var jsonEncoding = fieldEncoding{
+	tagKey:     "json",
+	defaultKey: func(name string) string { return name },
+	promote:    isPromoted,
+}
+
+// jsonObject is a JSON object with keys in a fixed order.
+type jsonObject []keyValue
+
+// MarshalJSON encodes the object with keys in their order.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, kv := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(kv.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(kv.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// recordToObject converts a generated record into an object that is marshalled
+// the same way encoding/json marshals the original struct, with fields in declaration order.
+func recordToObject(record *generator.Record) jsonObject {
+	fields := orderedFields(record, jsonEncoding)
+	for i, kv := range fields {
+		fields[i].value = jsonValue(kv.value)
+		if kv.tag.has("string") {
+			fields[i].value = jsonString(kv.field, fields[i].value)
+		}
+	}
+	return jsonObject(fields)
+}
+
+// jsonString applies the "string" json tag option,
+// that encodes strings, numbers and booleans inside a JSON string.
+func jsonString(field typeinfo.Field, value any) any {
+	base, _ := field.Type.Deref()
+	if value == nil || !base.Kind.IsBasic() {
+		return value
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	return string(encoded)
+}
+
+func jsonValue(value any) any {
+	switch value := value.(type) {
+	case *generator.Record:
+		return recordToObject(value)
+	case []any:
+		result := make([]any, len(value))
+		for i, elem := range value {
+			result[i] = jsonValue(elem)
+		}
+		return result
+	case []generator.MapEntry:
+		result := make(map[string]any, len(value))
+		for _, entry := range value {
+			result[jsonKey(entry.Key)] = jsonValue(entry.Value)
+		}
+		return result
+	case complex64, complex128:
+		// JSON has no complex numbers, they are written as strings like "(1+2i)"
+		return fmt.Sprint(value)
+	}
+	return value
+}
+
+// jsonKey formats a map key the way encoding/json does:
+// strings are used as is, text marshalers (e.g. uuid.UUID) are encoded as text.
+func jsonKey(key any) string {
+	switch key := key.(type) {
+	case string:
+		return key
+	case encoding.TextMarshaler:
+		if text, err := key.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(key)
+}
@@ -0,0 +1,229 @@
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+var (
+	intType    = &typeinfo.Type{Kind: typeinfo.Int}
+	stringType = &typeinfo.Type{Kind: typeinfo.String}
+)
+
+func TestRecordToObject_JsonTags(t *testing.T) {
+	audit := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Audit", PkgPath: "example.com/models"}
+	record := &generator.Record{Fields: []generator.RecordField{
+		{Field: typeinfo.Field{Name: "ID", Type: intType, Tag: `json:"id,string"`}, Value: 42},
+		{Field: typeinfo.Field{Name: "Name", Type: stringType, Tag: `json:"name"`}, Value: "John"},
+		{Field: typeinfo.Field{Name: "Password", Type: stringType, Tag: `json:"-"`}, Value: "secret"},
+		{Field: typeinfo.Field{Name: "Dash", Type: stringType, Tag: `json:"-,"`}, Value: "dash"},
+		{Field: typeinfo.Field{Name: "Nick", Type: stringType, Tag: `json:"nick,omitempty"`}, Value: ""},
+		{Field: typeinfo.Field{Name: "Score", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: intType}, Tag: `json:",omitempty"`}, Value: 0},
+		{Field: typeinfo.Field{Name: "internal", Type: stringType}, Value: "hidden"},
+		{Field: typeinfo.Field{Name: "Audit", Type: audit, Embedded: true}, Value: &generator.Record{Fields: []generator.RecordField{
+			{Field: typeinfo.Field{Name: "By", Type: stringType, Tag: `json:"by"`}, Value: "admin"},
+			{Field: typeinfo.Field{Name: "Name", Type: stringType}, Value: "Audit"},
+		}}},
+	}}
+
+	// fields are written in declaration order, promoted fields in place of the embedded struct
+	encoded, err := json.Marshal(recordToObject(record))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"id":"42","name":"John","-":"dash","Score":0,"by":"admin","Name":"Audit"}`; string(encoded) != want {
+		t.Errorf("Encoded record = %s, want %s", encoded, want)
+	}
+
+	type Audit struct {
+		By   string `json:"by"`
+		Name string
+	}
+	type User struct {
+		ID    int    `json:"id,string"`
+		Name  string `json:"name"`
+		Dash  string `json:"-,"`
+		Score *int   `json:",omitempty"`
+		Audit
+	}
+	var user User
+	if err := json.Unmarshal(encoded, &user); err != nil {
+		t.Fatalf("Failed to unmarshal generated JSON back: %v", err)
+	}
+	if user.ID != 42 || user.Name != "John" || user.Dash != "dash" || user.Score == nil || user.By != "admin" {
+		t.Errorf("Unexpected unmarshalled value: %+v", user)
+	}
+}
+
+func TestRecordToObject_Bytes(t *testing.T) {
+	bytesType := &typeinfo.Type{Kind: typeinfo.Slice, Elem: &typeinfo.Type{Kind: typeinfo.Uint8}}
+	record := &generator.Record{Fields: []generator.RecordField{
+		{Field: typeinfo.Field{Name: "Avatar", Type: bytesType}, Value: generator.Bytes{Data: []byte("hi!"), Encoding: "base64"}},
+		{Field: typeinfo.Field{Name: "Hash", Type: bytesType}, Value: generator.Bytes{Data: []byte{0xca, 0xfe}, Encoding: "hex"}},
+		{Field: typeinfo.Field{Name: "Empty", Type: bytesType, Tag: `json:",omitempty"`}, Value: generator.Bytes{Encoding: "base64"}},
+		{Field: typeinfo.Field{Name: "Phase", Type: &typeinfo.Type{Kind: typeinfo.Complex128}}, Value: complex(1, -2)},
+	}}
+
+	encoded, err := json.Marshal(recordToObject(record))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"Avatar":"aGkh","Hash":"cafe","Phase":"(1-2i)"}`; string(encoded) != want {
+		t.Errorf("Encoded record = %s, want %s", encoded, want)
+	}
+
+	// base64 is the encoding of byte slices in encoding/json
+	var decoded struct{ Avatar []byte }
+	if err := json.Unmarshal(encoded, &decoded); err != nil || string(decoded.Avatar) != "hi!" {
+		t.Errorf("Failed to unmarshal bytes back: %q, %v", decoded.Avatar, err)
+	}
+}
+
+func TestJsonWriter_WriteArray(t *testing.T) {
+	user := &typeinfo.Type{Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{
+		{Name: "ID", Type: intType, Tag: `json:"id"`},
+		{Name: "Tags", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: stringType}},
+	}}
+
+	for _, compact := range []bool{false, true} {
+		cfg := &config.Config{
+			Generation: config.GenerationConfig{Count: 3, RandSeed: 42, MaxDepth: 5},
+			JSON:       config.JSONConfig{Compact: compact},
+		}
+
+		// instances generated at once by a writer with the same seed
+		records, err := NewJsonWriter(nil, cfg, testutils.TestLogger()).(*JsonWriter).GenerateRecords("User", user)
+		if err != nil {
+			t.Fatalf("GenerateRecords() error = %v", err)
+		}
+		maps := make([]jsonObject, len(records))
+		for i, record := range records {
+			maps[i] = recordToObject(record)
+		}
+		want, _ := json.MarshalIndent(maps, "", " ")
+		if compact {
+			want, _ = json.Marshal(maps)
+		}
+
+		var got bytes.Buffer
+		out := bufio.NewWriter(&got)
+		w := NewJsonWriter(nil, cfg, testutils.TestLogger()).(*JsonWriter)
+		if err := w.writeArray(out, "", "User", user); err != nil {
+			t.Fatalf("writeArray() error = %v", err)
+		}
+		out.Flush()
+
+		if got.String() != string(want) {
+			t.Errorf("writeArray(compact=%v) =\n%s\nwant\n%s", compact, got.String(), want)
+		}
+	}
+}
+
+func TestJsonWriter_SingleFileLayouts(t *testing.T) {
+	structs := map[string]*typeinfo.Type{
+		"User":  {Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{{Name: "Name", Type: stringType, Tag: `json:"name"`}}},
+		"Order": {Kind: typeinfo.Struct, Name: "Order", Fields: []typeinfo.Field{{Name: "ID", Type: intType, Tag: `json:"id"`}}},
+	}
+
+	for _, compact := range []bool{false, true} {
+		for _, layout := range []string{config.KeyedLayout, config.ListLayout} {
+			path := filepath.Join(t.TempDir(), "mocks.json")
+			cfg := &config.Config{
+				Generation: config.GenerationConfig{Count: 2, RandSeed: 1, MaxDepth: 5},
+				Output:     config.OutputConfig{Path: path, OutputStrategy: config.SingleFile, Layout: layout},
+				JSON:       config.JSONConfig{Compact: compact},
+			}
+			if err := NewJsonWriter(structs, cfg, testutils.TestLogger()).Write(); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			data, _ := os.ReadFile(path)
+
+			type seed struct {
+				User  []struct{ Name string }
+				Order []struct{ ID int }
+			}
+			var got seed
+			if layout == config.KeyedLayout {
+				if err := json.Unmarshal(data, &got); err != nil {
+					t.Fatalf("Keyed output is not valid JSON: %v\n%s", err, data)
+				}
+			} else {
+				var items []struct {
+					Name  string          `json:"name"`
+					Items json.RawMessage `json:"items"`
+				}
+				if err := json.Unmarshal(data, &items); err != nil {
+					t.Fatalf("List output is not valid JSON: %v\n%s", err, data)
+				}
+				if len(items) != 2 || items[0].Name != "Order" || items[1].Name != "User" {
+					t.Fatalf("Unexpected list items: %s", data)
+				}
+				json.Unmarshal(items[0].Items, &got.Order)
+				json.Unmarshal(items[1].Items, &got.User)
+			}
+			if len(got.User) != 2 || len(got.Order) != 2 || got.User[0].Name == "" {
+				t.Errorf("Unexpected %s output (compact=%v):\n%s", layout, compact, data)
+			}
+		}
+	}
+}
+
+func TestJsonWriter_References(t *testing.T) {
+	structs := map[string]*typeinfo.Type{
+		"User": {Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{
+			{Name: "ID", Type: intType, Tag: `json:"id"`, MockTags: map[string]string{"min": "1", "max": "1000000"}},
+		}},
+		"Order": {Kind: typeinfo.Struct, Name: "Order", Fields: []typeinfo.Field{
+			{Name: "UserID", Type: intType, Tag: `json:"user_id"`, MockTags: map[string]string{"ref": "User.ID", "cardinality": "one"}},
+		}},
+	}
+	path := filepath.Join(t.TempDir(), "mocks.json")
+	cfg := &config.Config{
+		Generation: config.GenerationConfig{Count: 5, RandSeed: 1, MaxDepth: 5},
+		Output:     config.OutputConfig{Path: path, OutputStrategy: config.SingleFile, Layout: config.ListLayout},
+	}
+	if err := NewJsonWriter(structs, cfg, testutils.TestLogger()).Write(); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+
+	var items []struct {
+		Name  string `json:"name"`
+		Items []struct {
+			ID     int `json:"id"`
+			UserID int `json:"user_id"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &items); err != nil || len(items) != 2 || items[0].Name != "User" {
+		t.Fatalf("Referenced structs should be written first: %v\n%s", err, data)
+	}
+	ids := make(map[int]bool)
+	for _, user := range items[0].Items {
+		ids[user.ID] = true
+	}
+	for _, order := range items[1].Items {
+		if !ids[order.UserID] {
+			t.Errorf("UserID %d is not an ID of a generated user", order.UserID)
+		}
+		delete(ids, order.UserID) // cardinality=one
+	}
+	if len(ids) != 0 {
+		t.Errorf("Every user should be referenced once, not referenced: %v", ids)
+	}
+
+	structs["User"].Fields = append(structs["User"].Fields, typeinfo.Field{Name: "OrderID", Type: intType, MockTags: map[string]string{"ref": "Order.UserID"}})
+	err := NewJsonWriter(structs, cfg, testutils.TestLogger()).Write()
+	if !errors.Is(err, generator.ErrRefCycle) {
+		t.Errorf("Write() error = %v, want ErrRefCycle", err)
+	}
+}
@@ -0,0 +1,92 @@
+package writer
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// NdjsonWriter writes parsed structs as newline delimited JSON (JSON Lines):
+// an object per line, streamed through a buffer with constant memory usage.
+type NdjsonWriter struct {
+	BaseWriter
+}
+
+func NewNdjsonWriter(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return &NdjsonWriter{BaseWriter: newBaseWriter(structs, config, logger)}
+}
+
+// Write writes the parsed structs to NDJSON files.
+// In a single file every line is tagged with the struct name, see line.
+func (w *NdjsonWriter) Write() (err error) {
+	defer w.removeOnError(&err)
+	var file *os.File
+
+	if w.config.Output.OutputStrategy == config.SingleFile {
+		file, err = w.create(w.config.Output.Path)
+		if err != nil {
+			w.logger.Error("Failed to create single output file", "path", w.config.Output.Path, "error", err)
+			return err
+		}
+		w.logger.Info("Single output file created", "path", w.config.Output.Path)
+		defer file.Close()
+	}
+
+	for _, structName := range w.structNames() {
+		if w.config.Output.OutputStrategy == config.FilePerStruct {
+			fileName := w.filePath(structName, ".ndjson")
+			w.logger.Debug("Creating output file for struct", "fileName", fileName)
+			file, err = w.create(fileName)
+			if err != nil {
+				w.logger.Error("Failed to create output file for struct", "structName", structName, "error", err)
+				return err
+			}
+			w.logger.Info("Output file created for struct", "structName", structName)
+			defer file.Close()
+		}
+
+		out := bufio.NewWriterSize(file, 64*1024)
+		encoder := json.NewEncoder(out)
+		// generation errors are logged by EachRecord
+		err := w.EachRecord(structName, w.structs[structName], func(record *generator.Record) error {
+			if err := encoder.Encode(w.line(structName, record)); err != nil {
+				w.logger.Error("Failed to write NDJSON to file", "structName", structName, "error", err)
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := out.Flush(); err != nil {
+			w.logger.Error("Failed to write NDJSON to file", "structName", structName, "error", err)
+			return err
+		}
+		w.logger.Info("Successfully wrote NDJSON output for struct", "structName", structName)
+	}
+	return nil
+}
+
+// ndjsonItem is a line of the "list" layout.
+type ndjsonItem struct {
+	Name string     `json:"name"`
+	Item jsonObject `json:"item"`
+}
+
+// line returns the value encoded as a line. In a single file, instances are wrapped
+// according to the layout: {"User": {...}} for "keyed" and {"name": "User", "item": {...}} for "list".
+func (w *NdjsonWriter) line(structName string, record *generator.Record) any {
+	instance := recordToObject(record)
+	if w.config.Output.OutputStrategy != config.SingleFile {
+		return instance
+	}
+	if w.config.Output.Layout == config.ListLayout {
+		return ndjsonItem{Name: structName, Item: instance}
+	}
+	return map[string]any{structName: instance}
+}
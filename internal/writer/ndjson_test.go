@@ -0,0 +1,55 @@
+package writer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/testutils"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestNdjsonWriter_Write(t *testing.T) {
+	structs := map[string]*typeinfo.Type{
+		"User": {Kind: typeinfo.Struct, Name: "User", Fields: []typeinfo.Field{
+			{Name: "ID", Type: intType, Tag: `json:"id"`},
+			{Name: "Name", Type: stringType, Tag: `json:"name"`},
+		}},
+	}
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Generation: config.GenerationConfig{Count: 100, RandSeed: 1, MaxDepth: 5},
+		Output:     config.OutputConfig{Path: dir, OutputStrategy: config.FilePerStruct},
+	}
+	if err := NewNdjsonWriter(structs, cfg, testutils.TestLogger()).Write(); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(dir, "User.ndjson"))
+	if err != nil {
+		t.Fatalf("Failed to open output file: %v", err)
+	}
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var user struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &user); err != nil {
+			t.Fatalf("Line %d is not a JSON object: %v", lines+1, err)
+		}
+		if user.Name == "" {
+			t.Errorf("Line %d has no name: %s", lines+1, scanner.Text())
+		}
+		lines++
+	}
+	if lines != 100 {
+		t.Errorf("Write() wrote %d lines, want 100", lines)
+	}
+}
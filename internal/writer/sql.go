@@ -0,0 +1,297 @@
+package writer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// sqlDialect defines quoting and literal rules of a database.
+type sqlDialect struct {
+	identQuote      string // quote of identifiers
+	escapeBackslash bool   // whether backslashes in string literals are escape characters
+	trueLiteral     string
+	falseLiteral    string
+	timeLayout      string // layout of timestamps, formatted in UTC
+	blobFormat      string // format of binary literals with hex encoded bytes
+	quoteFloats     bool   // whether NaN and infinities are quoted literals like 'NaN', NULL otherwise
+}
+
+var sqlDialects = map[string]sqlDialect{
+	"postgres": {identQuote: `"`, trueLiteral: "TRUE", falseLiteral: "FALSE", timeLayout: "2006-01-02 15:04:05.999999-07:00", blobFormat: `'\x%s'::bytea`, quoteFloats: true},
+	"mysql":    {identQuote: "`", escapeBackslash: true, trueLiteral: "TRUE", falseLiteral: "FALSE", timeLayout: "2006-01-02 15:04:05.999999", blobFormat: "X'%s'"},
+	"sqlite":   {identQuote: `"`, trueLiteral: "1", falseLiteral: "0", timeLayout: "2006-01-02 15:04:05.999999999-07:00", blobFormat: "X'%s'"},
+}
+
+// ident quotes an identifier, like a table or column name.
+func (d sqlDialect) ident(name string) string {
+	return d.identQuote + strings.ReplaceAll(name, d.identQuote, d.identQuote+d.identQuote) + d.identQuote
+}
+
+// str returns a string literal.
+func (d sqlDialect) str(value string) string {
+	if d.escapeBackslash {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// literal returns an SQL literal of a generated value.
+// Byte slices are written as binary literals and durations as integer nanoseconds,
+// the way database/sql drivers store time.Duration. Nested structs, slices and maps are stored as JSON documents.
+func (d sqlDialect) literal(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return d.str(value)
+	case bool:
+		if value {
+			return d.trueLiteral
+		}
+		return d.falseLiteral
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr:
+		return fmt.Sprint(value)
+	case float32:
+		return d.float(float64(value), 32)
+	case float64:
+		return d.float(value, 64)
+	case time.Time:
+		return d.str(value.UTC().Format(d.timeLayout))
+	case time.Duration:
+		return strconv.FormatInt(int64(value), 10)
+	case generator.Bytes:
+		return fmt.Sprintf(d.blobFormat, hex.EncodeToString(value.Data))
+	case uuid.UUID:
+		return d.str(value.String())
+	case *generator.Record, []any, []generator.MapEntry:
+		encoded, err := json.Marshal(jsonValue(value))
+		if err != nil {
+			return "NULL"
+		}
+		return d.str(string(encoded))
+	}
+	return d.str(fmt.Sprint(value))
+}
+
+// float returns a float literal. NaN and infinities have no numeric literals,
+// they are written as quoted literals, like 'Infinity' in PostgreSQL, or as NULL.
+func (d sqlDialect) float(value float64, bitSize int) string {
+	switch {
+	case !math.IsNaN(value) && !math.IsInf(value, 0):
+		return strconv.FormatFloat(value, 'g', -1, bitSize)
+	case !d.quoteFloats:
+		return "NULL"
+	case math.IsNaN(value):
+		return "'NaN'"
+	case value > 0:
+		return "'Infinity'"
+	}
+	return "'-Infinity'"
+}
+
+// SqlWriter writes parsed structs as SQL INSERT statements
+type SqlWriter struct {
+	BaseWriter
+}
+
+func NewSqlWriter(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return &SqlWriter{BaseWriter: newBaseWriter(structs, config, logger)}
+}
+
+// Write writes the parsed structs to SQL files.
+func (w *SqlWriter) Write() (err error) {
+	defer w.removeOnError(&err)
+	dialect, ok := sqlDialects[w.config.SQL.Dialect]
+	if !ok {
+		w.logger.Error("Unsupported SQL dialect", "dialect", w.config.SQL.Dialect)
+		return fmt.Errorf("unsupported SQL dialect: %q", w.config.SQL.Dialect)
+	}
+
+	var file *os.File
+
+	if w.config.Output.OutputStrategy == config.SingleFile {
+		file, err = w.create(w.config.Output.Path)
+		if err != nil {
+			w.logger.Error("Failed to create single output file", "path", w.config.Output.Path, "error", err)
+			return err
+		}
+		w.logger.Info("Single output file created", "path", w.config.Output.Path)
+		defer file.Close()
+	}
+
+	for _, structName := range w.structNames() {
+		structType := w.structs[structName]
+		if w.config.Output.OutputStrategy == config.FilePerStruct {
+			fileName := w.filePath(structName, ".sql")
+			w.logger.Debug("Creating output file for struct", "fileName", fileName)
+			file, err = w.create(fileName)
+			if err != nil {
+				w.logger.Error("Failed to create output file for struct", "structName", structName, "error", err)
+				return err
+			}
+			w.logger.Info("Output file created for struct", "structName", structName)
+			defer file.Close()
+		}
+
+		records, err := w.GenerateRecords(structName, structType)
+		if err != nil {
+			return err
+		}
+		columns := sqlColumns(structType)
+		if len(columns) == 0 {
+			w.logger.Warn("Struct has no columns; skipping", "structName", structName)
+			continue
+		}
+		statements := insertStatements(dialect, sqlTable(structType), columns, records, w.config.SQL.BatchSize)
+		if _, err := file.WriteString(statements); err != nil {
+			w.logger.Error("Failed to write SQL to file", "structName", structName, "error", err)
+			return err
+		}
+		w.logger.Info("Successfully wrote SQL output for struct", "structName", structName)
+	}
+	return nil
+}
+
+// insertStatements returns INSERT statements of the records, with at most batchSize rows per statement.
+// A non-positive batchSize inserts all records with a single statement.
+func insertStatements(dialect sqlDialect, table string, columns []column, records []*generator.Record, batchSize int) string {
+	if batchSize <= 0 {
+		batchSize = len(records)
+	}
+
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = dialect.ident(col.name)
+	}
+	header := fmt.Sprintf("INSERT INTO %s (%s) VALUES\n", dialect.ident(table), strings.Join(names, ", "))
+
+	var sql strings.Builder
+	for start := 0; start < len(records); start += batchSize {
+		batch := records[start:min(start+batchSize, len(records))]
+		sql.WriteString(header)
+		for i, record := range batch {
+			values := make([]string, len(columns))
+			for j, col := range columns {
+				values[j] = dialect.literal(col.value(record))
+			}
+			sql.WriteString("\t(" + strings.Join(values, ", ") + ")")
+			if i < len(batch)-1 {
+				sql.WriteString(",\n")
+			}
+		}
+		sql.WriteString(";\n\n")
+	}
+	return sql.String()
+}
+
+// sqlColumns returns the columns of a struct. Columns of embedded structs are promoted to the table,
+// as sqlx and gorm do, unless the embedded field is named by a tag.
+func sqlColumns(t *typeinfo.Type) []column {
+	return uniqueColumns(collectSqlColumns(t, nil, nil))
+}
+
+func collectSqlColumns(t *typeinfo.Type, path []string, embedding []*typeinfo.Type) []column {
+	var columns []column
+	for _, field := range t.Fields {
+		tag := parseFieldTag(field, "db")
+		if !isEncoded(field, tag) {
+			continue
+		}
+		name, ok := sqlColumnName(field, tag)
+		if !ok {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], field.Name)
+		base, _ := field.Type.Deref()
+		_, generated := generator.LookupFactory(base)
+		if isPromoted(field, tag) && gormOptions(field.Tag)["column"] == "" && !generated {
+			if slices.Contains(embedding, base) {
+				continue // embedded pointer to the struct itself
+			}
+			columns = append(columns, collectSqlColumns(base, fieldPath, append(embedding[:len(embedding):len(embedding)], t))...)
+			continue
+		}
+		columns = append(columns, column{name: name, path: fieldPath})
+	}
+	return columns
+}
+
+// sqlColumnName returns the column name of the field: the name from the "db" tag,
+// the "column" option of the "gorm" tag or the snake cased field name.
+// Fields with "-" tags are not stored.
+func sqlColumnName(field typeinfo.Field, tag fieldTag) (string, bool) {
+	if tag.name != "" {
+		return tag.name, true
+	}
+	gorm := gormOptions(field.Tag)
+	if _, ok := gorm["-"]; ok {
+		return "", false
+	}
+	if column := gorm["column"]; column != "" {
+		return column, true
+	}
+	return toSnakeCase(field.Name), true
+}
+
+// sqlTable returns the table name of a struct: the name from the "db" tag or the "table" option
+// of the "gorm" tag of the blank "_" field, or the snake cased plural of the struct name.
+func sqlTable(t *typeinfo.Type) string {
+	if name, _, _ := strings.Cut(t.Tag.Get("db"), ","); name != "" && name != "-" {
+		return name
+	}
+	if table := gormOptions(t.Tag)["table"]; table != "" {
+		return table
+	}
+	return toSnakeCase(plural(t.Name))
+}
+
+// gormOptions parses a gorm tag like `gorm:"column:user_name;not null"`.
+// Option keys are case insensitive and returned in lower case.
+func gormOptions(tag reflect.StructTag) map[string]string {
+	options := make(map[string]string)
+	for _, option := range strings.Split(tag.Get("gorm"), ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), ":")
+		if key == "" {
+			continue
+		}
+		if strings.HasPrefix(key, "-") {
+			key = "-" // "-", "-:all", "-:migration"
+		}
+		options[strings.ToLower(key)] = value
+	}
+	return options
+}
+
+// toSnakeCase converts a Go identifier to snake case, e.g. "UserID" -> "user_id".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var result strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			acronymEnd := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || acronymEnd {
+				result.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
@@ -0,0 +1,166 @@
+package writer
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+func TestInsertStatements_Dialects(t *testing.T) {
+	timeType := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "time", Name: "Time"}
+	uuidType := &typeinfo.Type{Kind: typeinfo.Array, Len: 16, PkgPath: "github.com/google/uuid", Name: "UUID", Elem: &typeinfo.Type{Kind: typeinfo.Uint8}}
+	audit := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Audit", Fields: []typeinfo.Field{
+		{Name: "CreatedAt", Type: timeType},
+	}}
+	user := &typeinfo.Type{Kind: typeinfo.Struct, Name: "UserRole", Tag: `db:"user_roles"`, Fields: []typeinfo.Field{
+		{Name: "ID", Type: uuidType},
+		{Name: "Name", Type: stringType, Tag: `db:"full_name"`},
+		{Name: "Active", Type: &typeinfo.Type{Kind: typeinfo.Bool}, Tag: `gorm:"column:is_active;not null"`},
+		{Name: "Manager", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: intType}},
+		{Name: "Tags", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: stringType}},
+		{Name: "Password", Type: stringType, Tag: `db:"-"`},
+		{Name: "Audit", Type: audit, Embedded: true},
+	}}
+
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	created := time.Date(2024, time.March, 1, 12, 30, 0, 500000, time.FixedZone("CET", 3600))
+	record := func(name string) *generator.Record {
+		return &generator.Record{Type: user, Fields: []generator.RecordField{
+			{Field: user.Fields[0], Value: id},
+			{Field: user.Fields[1], Value: name},
+			{Field: user.Fields[2], Value: true},
+			{Field: user.Fields[3], Value: nil},
+			{Field: user.Fields[4], Value: []any{"a"}},
+			{Field: user.Fields[5], Value: "secret"},
+			{Field: user.Fields[6], Value: &generator.Record{Type: audit, Fields: []generator.RecordField{
+				{Field: audit.Fields[0], Value: created},
+			}}},
+		}}
+	}
+	records := []*generator.Record{record(`O'Brien \ 1`), record("Smith"), record("Doe")}
+
+	tests := []struct {
+		dialect string
+		want    string
+	}{
+		{"postgres", `INSERT INTO "user_roles" ("id", "full_name", "is_active", "manager", "tags", "created_at") VALUES
+	('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'O''Brien \ 1', TRUE, NULL, '["a"]', '2024-03-01 11:30:00.0005+00:00'),
+	('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'Smith', TRUE, NULL, '["a"]', '2024-03-01 11:30:00.0005+00:00');
+
+INSERT INTO "user_roles" ("id", "full_name", "is_active", "manager", "tags", "created_at") VALUES
+	('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'Doe', TRUE, NULL, '["a"]', '2024-03-01 11:30:00.0005+00:00');
+
+`},
+		{"mysql", "INSERT INTO `user_roles` (`id`, `full_name`, `is_active`, `manager`, `tags`, `created_at`) VALUES\n" +
+			"\t('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'O''Brien \\\\ 1', TRUE, NULL, '[\"a\"]', '2024-03-01 11:30:00.0005'),\n" +
+			"\t('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'Smith', TRUE, NULL, '[\"a\"]', '2024-03-01 11:30:00.0005');\n\n" +
+			"INSERT INTO `user_roles` (`id`, `full_name`, `is_active`, `manager`, `tags`, `created_at`) VALUES\n" +
+			"\t('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'Doe', TRUE, NULL, '[\"a\"]', '2024-03-01 11:30:00.0005');\n\n"},
+		{"sqlite", `INSERT INTO "user_roles" ("id", "full_name", "is_active", "manager", "tags", "created_at") VALUES
+	('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'O''Brien \ 1', 1, NULL, '["a"]', '2024-03-01 11:30:00.0005+00:00'),
+	('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'Smith', 1, NULL, '["a"]', '2024-03-01 11:30:00.0005+00:00');
+
+INSERT INTO "user_roles" ("id", "full_name", "is_active", "manager", "tags", "created_at") VALUES
+	('6ba7b810-9dad-11d1-80b4-00c04fd430c8', 'Doe', 1, NULL, '["a"]', '2024-03-01 11:30:00.0005+00:00');
+
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			got := insertStatements(sqlDialects[tt.dialect], sqlTable(user), sqlColumns(user), records, 2)
+			if got != tt.want {
+				t.Errorf("insertStatements() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSqlDialect_Literal(t *testing.T) {
+	bytes := generator.Bytes{Data: []byte{0xca, 0xfe, 0x01}, Encoding: "base64"}
+	tests := []struct {
+		dialect string
+		bytes   string
+		floats  []string // NaN, +Inf, -Inf
+	}{
+		{"postgres", `'\xcafe01'::bytea`, []string{"'NaN'", "'Infinity'", "'-Infinity'"}},
+		{"mysql", "X'cafe01'", []string{"NULL", "NULL", "NULL"}},
+		{"sqlite", "X'cafe01'", []string{"NULL", "NULL", "NULL"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			dialect := sqlDialects[tt.dialect]
+			if got := dialect.literal(bytes); got != tt.bytes {
+				t.Errorf("literal(%v) = %s, want %s", bytes.Data, got, tt.bytes)
+			}
+			for i, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+				if got := dialect.literal(value); got != tt.floats[i] {
+					t.Errorf("literal(%v) = %s, want %s", value, got, tt.floats[i])
+				}
+			}
+			if got := dialect.literal(float32(1.5)); got != "1.5" {
+				t.Errorf("literal(1.5) = %s, want 1.5", got)
+			}
+		})
+	}
+}
+
+func TestSqlDialect_DurationLiteral(t *testing.T) {
+	for name, dialect := range sqlDialects {
+		if got := dialect.literal(90 * time.Minute); got != "5400000000000" {
+			t.Errorf("%s literal(1h30m) = %s, want integer nanoseconds", name, got)
+		}
+	}
+}
+
+func TestSqlTable(t *testing.T) {
+	tests := []struct {
+		typ  *typeinfo.Type
+		want string
+	}{
+		{&typeinfo.Type{Name: "User"}, "users"},
+		{&typeinfo.Type{Name: "UserAddress"}, "user_addresses"},
+		{&typeinfo.Type{Name: "User", Tag: `db:"accounts"`}, "accounts"},
+		{&typeinfo.Type{Name: "User", Tag: `gorm:"table:members"`}, "members"},
+	}
+	for _, tt := range tests {
+		if got := sqlTable(tt.typ); got != tt.want {
+			t.Errorf("sqlTable(%s) = %q, want %q", tt.typ.Name, got, tt.want)
+		}
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"ID":         "id",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"CreatedAt":  "created_at",
+		"Address2":   "address2",
+		"name":       "name",
+	}
+	for name, want := range tests {
+		if got := toSnakeCase(name); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSqlColumns_SelfEmbedding(t *testing.T) {
+	node := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Node"}
+	node.Fields = []typeinfo.Field{
+		{Name: "Value", Type: intType},
+		{Name: "Node", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: node}, Embedded: true},
+	}
+	got := sqlColumns(node)
+	want := []column{{name: "value", path: []string{"Value"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sqlColumns() = %+v, want %+v", got, want)
+	}
+}
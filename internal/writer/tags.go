@@ -0,0 +1,161 @@
+package writer
+
+import (
+	"go/token"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// fieldTag is a parsed encoding struct tag, like `json:"name,omitempty"`.
+type fieldTag struct {
+	name    string   // name from the tag, empty if not provided
+	options []string // options after the name, e.g. "omitempty"
+	skip    bool     // true for "-" tags
+}
+
+// parseFieldTag parses the struct tag with the given key (json, yaml etc.) of the field.
+func parseFieldTag(field typeinfo.Field, key string) fieldTag {
+	tag, ok := field.Tag.Lookup(key)
+	if !ok {
+		return fieldTag{}
+	}
+	if tag == "-" {
+		return fieldTag{skip: true}
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	result := fieldTag{name: name}
+	if options != "" {
+		result.options = strings.Split(options, ",")
+	}
+	return result
+}
+
+// has reports whether the tag has the given option.
+func (t fieldTag) has(option string) bool {
+	return slices.Contains(t.options, option)
+}
+
+// keyOf returns the name of the field in the encoded output:
+// the name from the tag if provided and the field name otherwise.
+func (t fieldTag) keyOf(field typeinfo.Field) string {
+	if t.name != "" {
+		return t.name
+	}
+	return field.Name
+}
+
+// isEncoded reports whether an encoder based on reflection, like encoding/json,
+// would encode the field. Unexported fields are skipped unless they are embedded structs.
+func isEncoded(field typeinfo.Field, tag fieldTag) bool {
+	if tag.skip {
+		return false
+	}
+	if token.IsExported(field.Name) {
+		return true
+	}
+	base, _ := field.Type.Deref()
+	return field.Embedded && base.Kind == typeinfo.Struct
+}
+
+// isPromoted reports whether the fields of an embedded struct
+// are promoted to the outer object instead of being nested under the field name.
+func isPromoted(field typeinfo.Field, tag fieldTag) bool {
+	base, _ := field.Type.Deref()
+	return field.Embedded && tag.name == "" && base.Kind == typeinfo.Struct
+}
+
+// isEmptyField reports whether a generated field value is empty in terms of the "omitempty" option.
+// Pointers are empty only when nil, even if they point to an empty value.
+func isEmptyField(field generator.RecordField) bool {
+	if field.Field.Type.Kind == typeinfo.Pointer {
+		return field.Value == nil
+	}
+	return isEmptyValue(field.Value)
+}
+
+// isEmptyValue reports whether a generated value is empty.
+// Like in encoding/json, structs are never considered empty.
+func isEmptyValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	if bytes, ok := value.(generator.Bytes); ok {
+		return len(bytes.Data) == 0
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return v.IsZero()
+	}
+	return false
+}
+
+// keyValue is an encoded field of a record.
+type keyValue struct {
+	key   string
+	value any
+	tag   fieldTag
+	field typeinfo.Field
+}
+
+// fieldEncoding describes how an encoder names and promotes struct fields.
+type fieldEncoding struct {
+	tagKey     string                                        // struct tag key, e.g. "yaml"
+	defaultKey func(name string) string                      // key of fields without a name in the tag
+	promote    func(field typeinfo.Field, tag fieldTag) bool // whether fields of a nested struct are promoted to the outer object
+}
+
+// orderedFields returns the encoded fields of a record in declaration order,
+// with fields of promoted structs in place of the struct field.
+// Fields of the outer struct take precedence over promoted fields with the same key.
+func orderedFields(record *generator.Record, encoding fieldEncoding) []keyValue {
+	type candidate struct {
+		keyValue
+		promoted bool
+	}
+	var candidates []candidate
+	outer := make(map[string]bool)
+	for _, field := range record.Fields {
+		tag := parseFieldTag(field.Field, encoding.tagKey)
+		if !isEncoded(field.Field, tag) {
+			continue
+		}
+		if tag.has("omitempty") && isEmptyField(field) {
+			continue
+		}
+
+		if nested, ok := field.Value.(*generator.Record); ok && encoding.promote(field.Field, tag) {
+			for _, kv := range orderedFields(nested, encoding) {
+				candidates = append(candidates, candidate{kv, true})
+			}
+			continue
+		}
+
+		key := tag.name
+		if key == "" {
+			key = encoding.defaultKey(field.Field.Name)
+		}
+		candidates = append(candidates, candidate{keyValue{key: key, value: field.Value, tag: tag, field: field.Field}, false})
+		outer[key] = true
+	}
+
+	result := make([]keyValue, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		if seen[c.key] || (c.promoted && outer[c.key]) {
+			continue
+		}
+		seen[c.key] = true
+		result = append(result, c.keyValue)
+	}
+	return result
+}
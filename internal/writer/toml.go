@@ -0,0 +1,285 @@
+package writer
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+)
+
+// tomlEncoding follows github.com/BurntSushi/toml: keys of untagged fields are field names,
+// fields of embedded structs are promoted unless the embedded field is named by a tag.
+var tomlEncoding = fieldEncoding{
+	tagKey:     "toml",
+	defaultKey: func(name string) string { return name },
+	promote:    isPromoted,
+}
+
+// TomlWriter writes parsed structs in a toml format.
+// Instances of a struct are written as an array of tables named after the struct, e.g. [[User]].
+// With the "list" layout, a single file holds an array of [[structs]] tables with "name" and "items".
+type TomlWriter struct {
+	BaseWriter
+}
+
+func NewTomlWriter(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return &TomlWriter{BaseWriter: newBaseWriter(structs, config, logger)}
+}
+
+// Write writes the parsed structs to TOML files.
+func (w *TomlWriter) Write() (err error) {
+	defer w.removeOnError(&err)
+	var file *os.File
+
+	if w.config.Output.OutputStrategy == config.SingleFile {
+		file, err = w.create(w.config.Output.Path)
+		if err != nil {
+			w.logger.Error("Failed to create single output file", "path", w.config.Output.Path, "error", err)
+			return err
+		}
+		w.logger.Info("Single output file created", "path", w.config.Output.Path)
+		defer file.Close()
+	}
+
+	for i, structName := range w.structNames() {
+		if w.config.Output.OutputStrategy == config.FilePerStruct {
+			fileName := w.filePath(structName, ".toml")
+			w.logger.Debug("Creating output file for struct", "fileName", fileName)
+			file, err = w.create(fileName)
+			if err != nil {
+				w.logger.Error("Failed to create output file for struct", "structName", structName, "error", err)
+				return err
+			}
+			w.logger.Info("Output file created for struct", "structName", structName)
+			defer file.Close()
+		}
+
+		records, err := w.GenerateRecords(structName, w.structs[structName])
+		if err != nil {
+			return err
+		}
+		var toml strings.Builder
+		if w.config.Output.OutputStrategy == config.SingleFile && w.config.Output.Layout == config.ListLayout {
+			items := make([]any, len(records))
+			for j, record := range records {
+				items[j] = record
+			}
+			writeTomlTable(&toml, []string{"structs"}, true, []keyValue{{key: "name", value: structName}, {key: "items", value: items}})
+		} else {
+			for _, record := range records {
+				writeTomlTable(&toml, []string{structName}, true, tomlEntries(record))
+			}
+		}
+		separator := ""
+		if i > 0 && w.config.Output.OutputStrategy == config.SingleFile {
+			separator = "\n" // separates tables of the previous struct
+		}
+		if _, err := file.WriteString(separator + toml.String()); err != nil {
+			w.logger.Error("Failed to write TOML to file", "structName", structName, "error", err)
+			return err
+		}
+		w.logger.Info("Successfully wrote TOML output for struct", "structName", structName)
+	}
+	return nil
+}
+
+// tomlEntries returns the keys and values of a table: a struct record or a map.
+func tomlEntries(value any) []keyValue {
+	switch value := value.(type) {
+	case *generator.Record:
+		return orderedFields(value, tomlEncoding)
+	case []generator.MapEntry:
+		entries := make([]keyValue, len(value))
+		for i, entry := range value {
+			entries[i] = keyValue{key: jsonKey(entry.Key), value: entry.Value}
+		}
+		return entries
+	}
+	return nil
+}
+
+func isTomlTable(value any) bool {
+	switch value.(type) {
+	case *generator.Record, []generator.MapEntry:
+		return true
+	}
+	return false
+}
+
+// isTomlTableArray reports whether the value is a non-empty array of tables.
+func isTomlTableArray(value any) bool {
+	elems, ok := value.([]any)
+	if !ok {
+		return false
+	}
+	tables := 0
+	for _, elem := range elems {
+		if isTomlTable(elem) {
+			tables++
+		} else if elem != nil {
+			return false
+		}
+	}
+	return tables > 0
+}
+
+// writeTomlTable writes a table with its header, key/value pairs first,
+// then sub-tables and arrays of tables, as TOML requires.
+// Nil values are omitted, since TOML has no null.
+func writeTomlTable(toml *strings.Builder, path []string, arrayElem bool, entries []keyValue) {
+	header := make([]string, len(path))
+	for i, key := range path {
+		header[i] = tomlKey(key)
+	}
+	if toml.Len() > 0 {
+		toml.WriteString("\n")
+	}
+	if arrayElem {
+		toml.WriteString("[[" + strings.Join(header, ".") + "]]\n")
+	} else {
+		toml.WriteString("[" + strings.Join(header, ".") + "]\n")
+	}
+
+	for _, entry := range entries {
+		if entry.value == nil || isTomlTable(entry.value) || isTomlTableArray(entry.value) {
+			continue
+		}
+		toml.WriteString(tomlKey(entry.key) + " = " + tomlValue(entry.value) + "\n")
+	}
+
+	for _, entry := range entries {
+		subPath := append(path[:len(path):len(path)], entry.key)
+		switch {
+		case isTomlTable(entry.value):
+			writeTomlTable(toml, subPath, false, tomlEntries(entry.value))
+		case isTomlTableArray(entry.value):
+			for _, elem := range entry.value.([]any) {
+				if elem != nil {
+					writeTomlTable(toml, subPath, true, tomlEntries(elem))
+				}
+			}
+		}
+	}
+}
+
+// tomlValue formats a value of a key/value pair or an array element.
+// Tables nested in arrays are written inline.
+func tomlValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return tomlString(value)
+	case bool:
+		return strconv.FormatBool(value)
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		return fmt.Sprint(value)
+	case uint, uint64, uintptr:
+		// TOML integers are 64-bit signed, larger values are kept as strings
+		if reflect.ValueOf(value).Uint() > math.MaxInt64 {
+			return tomlString(fmt.Sprint(value))
+		}
+		return fmt.Sprint(value)
+	case float32:
+		return tomlFloat(float64(value), 32)
+	case float64:
+		return tomlFloat(value, 64)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case encoding.TextMarshaler:
+		if text, err := value.MarshalText(); err == nil {
+			return tomlString(string(text))
+		}
+	case []any:
+		elems := make([]string, 0, len(value))
+		for _, elem := range value {
+			if elem != nil {
+				elems = append(elems, tomlValue(elem))
+			}
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case *generator.Record, []generator.MapEntry:
+		var pairs []string
+		for _, entry := range tomlEntries(value) {
+			if entry.value != nil {
+				pairs = append(pairs, tomlKey(entry.key)+" = "+tomlValue(entry.value))
+			}
+		}
+		if len(pairs) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(pairs, ", ") + " }"
+	}
+	return tomlString(fmt.Sprint(value))
+}
+
+// tomlFloat formats a float, which in TOML must contain a fraction or an exponent.
+func tomlFloat(value float64, bitSize int) string {
+	switch {
+	case math.IsNaN(value):
+		return "nan"
+	case math.IsInf(value, 1):
+		return "inf"
+	case math.IsInf(value, -1):
+		return "-inf"
+	}
+	formatted := strconv.FormatFloat(value, 'g', -1, bitSize)
+	if !strings.ContainsAny(formatted, ".e") {
+		formatted += ".0"
+	}
+	return formatted
+}
+
+// tomlKey returns a bare key if possible, and a quoted key otherwise.
+func tomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return tomlString(key)
+		}
+	}
+	return key
+}
+
+// tomlString returns a basic string. Unlike Go, TOML has no \x escapes,
+// control characters are escaped as \uXXXX.
+func tomlString(value string) string {
+	var s strings.Builder
+	s.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			s.WriteString(`\"`)
+		case '\\':
+			s.WriteString(`\\`)
+		case '\b':
+			s.WriteString(`\b`)
+		case '\t':
+			s.WriteString(`\t`)
+		case '\n':
+			s.WriteString(`\n`)
+		case '\f':
+			s.WriteString(`\f`)
+		case '\r':
+			s.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+				fmt.Fprintf(&s, `\u%04X`, r)
+			} else {
+				s.WriteRune(r)
+			}
+		}
+	}
+	s.WriteByte('"')
+	return s.String()
+}
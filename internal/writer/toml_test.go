@@ -0,0 +1,52 @@
+package writer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteTomlTable(t *testing.T) {
+	var got strings.Builder
+	writeTomlTable(&got, []string{"Settings"}, true, tomlEntries(testSettings()))
+
+	want := `[[Settings]]
+Name = "app \"main\""
+id = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+version = 2
+Ratio = 3.0
+tags = ["a", "b"]
+Updated = 2024-03-01T12:30:00Z
+
+[Settings.labels]
+z = "1"
+"a b" = "2"
+
+[[Settings.ports]]
+number = 80
+
+[[Settings.ports]]
+number = 443
+`
+	if got.String() != want {
+		t.Errorf("writeTomlTable() =\n%s\nwant\n%s", got.String(), want)
+	}
+}
+
+func TestTomlValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"tab\there\x01", `"tab\there\u0001"`},
+		{uint64(1 << 63), `"9223372036854775808"`},
+		{uint64(42), "42"},
+		{float32(-1.5), "-1.5"},
+		{1e300, "1e+300"},
+		{[]any{1, nil, 2}, "[1, 2]"},
+	}
+	for _, tt := range tests {
+		if got := tomlValue(tt.value); got != tt.want {
+			t.Errorf("tomlValue(%v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package writer
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlEncoding follows gopkg.in/yaml.v3: keys of untagged fields are lower cased field names,
+// fields of nested structs are promoted only with the "inline" option.
+var yamlEncoding = fieldEncoding{
+	tagKey:     "yaml",
+	defaultKey: strings.ToLower,
+	promote: func(field typeinfo.Field, tag fieldTag) bool {
+		return tag.has("inline")
+	},
+}
+
+// YamlWriter writes parsed structs in a yaml format
+type YamlWriter struct {
+	BaseWriter
+}
+
+func NewYamlWriter(structs map[string]*typeinfo.Type, config *config.Config, logger *slog.Logger) Writer {
+	return &YamlWriter{BaseWriter: newBaseWriter(structs, config, logger)}
+}
+
+// Write writes the parsed structs to YAML files.
+// A file per struct holds a sequence of instances. A single file holds a mapping of struct names
+// to sequences of their instances, or with the "list" layout a sequence of mappings with "name" and "items".
+func (w *YamlWriter) Write() (err error) {
+	defer w.removeOnError(&err)
+	if w.config.Output.OutputStrategy == config.SingleFile {
+		keyed := w.config.Output.Layout != config.ListLayout
+		document := &yaml.Node{Kind: yaml.MappingNode}
+		if !keyed {
+			document.Kind = yaml.SequenceNode
+		}
+		for _, structName := range w.structNames() {
+			instances, err := w.instances(structName)
+			if err != nil {
+				return err
+			}
+			if keyed {
+				document.Content = append(document.Content, yamlString(structName), instances)
+			} else {
+				document.Content = append(document.Content, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+					yamlString("name"), yamlString(structName),
+					yamlString("items"), instances,
+				}})
+			}
+		}
+		return w.writeFile(w.config.Output.Path, document)
+	}
+
+	for _, structName := range w.structNames() {
+		instances, err := w.instances(structName)
+		if err != nil {
+			return err
+		}
+		if err := w.writeFile(w.filePath(structName, ".yaml"), instances); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// instances generates instances of the struct as a sequence node.
+func (w *YamlWriter) instances(structName string) (*yaml.Node, error) {
+	records, err := w.GenerateRecords(structName, w.structs[structName])
+	if err != nil {
+		return nil, err
+	}
+	sequence := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, record := range records {
+		node, err := yamlNode(record)
+		if err != nil {
+			w.logger.Error("Failed to encode YAML", "structName", structName, "error", err)
+			return nil, err
+		}
+		sequence.Content = append(sequence.Content, node)
+	}
+	return sequence, nil
+}
+
+func (w *YamlWriter) writeFile(fileName string, document *yaml.Node) error {
+	file, err := w.create(fileName)
+	if err != nil {
+		w.logger.Error("Failed to create output file", "fileName", fileName, "error", err)
+		return err
+	}
+	defer file.Close()
+
+	encoder := yaml.NewEncoder(file)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		w.logger.Error("Failed to write YAML to file", "fileName", fileName, "error", err)
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		w.logger.Error("Failed to write YAML to file", "fileName", fileName, "error", err)
+		return err
+	}
+	w.logger.Info("Successfully wrote YAML output", "fileName", fileName)
+	return nil
+}
+
+// yamlNode converts a generated value into a YAML node, keeping the declaration order of struct fields.
+func yamlNode(value any) (*yaml.Node, error) {
+	switch value := value.(type) {
+	case *generator.Record:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, field := range orderedFields(value, yamlEncoding) {
+			fieldNode, err := yamlNode(field.value)
+			if err != nil {
+				return nil, err
+			}
+			if field.tag.has("flow") {
+				fieldNode.Style = yaml.FlowStyle
+			}
+			node.Content = append(node.Content, yamlString(field.key), fieldNode)
+		}
+		return node, nil
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, elem := range value {
+			elemNode, err := yamlNode(elem)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, elemNode)
+		}
+		return node, nil
+	case []generator.MapEntry:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, entry := range value {
+			keyNode, err := yamlNode(entry.Key)
+			if err != nil {
+				return nil, err
+			}
+			valueNode, err := yamlNode(entry.Value)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, keyNode, valueNode)
+		}
+		return node, nil
+	case complex64, complex128:
+		// YAML has no complex numbers, they are written as strings like "(1+2i)"
+		return yamlString(fmt.Sprint(value)), nil
+	}
+
+	// scalars, including time.Time and text marshalers like uuid.UUID
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func yamlString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,103 @@
+package writer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/typeinfo"
+	"gopkg.in/yaml.v3"
+)
+
+// testSettings returns a record of a struct with nested structs, slices and maps,
+// used to test encoders which keep the field declaration order.
+func testSettings() *generator.Record {
+	timeType := &typeinfo.Type{Kind: typeinfo.Struct, PkgPath: "time", Name: "Time"}
+	uuidType := &typeinfo.Type{Kind: typeinfo.Array, Len: 16, PkgPath: "github.com/google/uuid", Name: "UUID", Elem: &typeinfo.Type{Kind: typeinfo.Uint8}}
+	meta := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Meta", Fields: []typeinfo.Field{
+		{Name: "Version", Type: intType, Tag: `yaml:"version" toml:"version"`},
+	}}
+	port := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Port", Fields: []typeinfo.Field{
+		{Name: "Number", Type: intType, Tag: `yaml:"number" toml:"number"`},
+	}}
+	settings := &typeinfo.Type{Kind: typeinfo.Struct, Name: "Settings", Fields: []typeinfo.Field{
+		{Name: "Name", Type: stringType},
+		{Name: "ID", Type: uuidType, Tag: `yaml:"id" toml:"id"`},
+		{Name: "Meta", Type: meta, Embedded: true, Tag: `yaml:",inline"`},
+		{Name: "Ratio", Type: &typeinfo.Type{Kind: typeinfo.Float64}},
+		{Name: "Secret", Type: stringType, Tag: `yaml:"-" toml:"-"`},
+		{Name: "Comment", Type: stringType, Tag: `yaml:",omitempty" toml:",omitempty"`},
+		{Name: "Parent", Type: &typeinfo.Type{Kind: typeinfo.Pointer, Elem: meta}},
+		{Name: "Tags", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: stringType}, Tag: `yaml:"tags,flow" toml:"tags"`},
+		{Name: "Labels", Type: &typeinfo.Type{Kind: typeinfo.Map, Key: stringType, Elem: stringType}, Tag: `yaml:"labels" toml:"labels"`},
+		{Name: "Ports", Type: &typeinfo.Type{Kind: typeinfo.Slice, Elem: port}, Tag: `yaml:"ports" toml:"ports"`},
+		{Name: "Updated", Type: timeType},
+	}}
+
+	return &generator.Record{Type: settings, Fields: []generator.RecordField{
+		{Field: settings.Fields[0], Value: "app \"main\""},
+		{Field: settings.Fields[1], Value: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")},
+		{Field: settings.Fields[2], Value: &generator.Record{Type: meta, Fields: []generator.RecordField{
+			{Field: meta.Fields[0], Value: 2},
+		}}},
+		{Field: settings.Fields[3], Value: 3.0},
+		{Field: settings.Fields[4], Value: "secret"},
+		{Field: settings.Fields[5], Value: ""},
+		{Field: settings.Fields[6], Value: nil},
+		{Field: settings.Fields[7], Value: []any{"a", "b"}},
+		{Field: settings.Fields[8], Value: []generator.MapEntry{{Key: "z", Value: "1"}, {Key: "a b", Value: "2"}}},
+		{Field: settings.Fields[9], Value: []any{
+			&generator.Record{Type: port, Fields: []generator.RecordField{{Field: port.Fields[0], Value: 80}}},
+			&generator.Record{Type: port, Fields: []generator.RecordField{{Field: port.Fields[0], Value: 443}}},
+		}},
+		{Field: settings.Fields[10], Value: time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)},
+	}}
+}
+
+func TestYamlNode_FieldOrder(t *testing.T) {
+	node, err := yamlNode(testSettings())
+	if err != nil {
+		t.Fatalf("yamlNode() error = %v", err)
+	}
+	got, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatalf("Failed to marshal YAML: %v", err)
+	}
+
+	want := `name: app "main"
+id: 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+version: 2
+ratio: 3
+parent: null
+tags: [a, b]
+labels:
+    z: "1"
+    a b: "2"
+ports:
+    - number: 80
+    - number: 443
+updated: 2024-03-01T12:30:00Z
+`
+	if string(got) != want {
+		t.Errorf("yamlNode() =\n%s\nwant\n%s", got, want)
+	}
+
+	type Port struct {
+		Number int `yaml:"number"`
+	}
+	var settings struct {
+		Name    string
+		ID      uuid.UUID `yaml:"id"`
+		Version int       `yaml:"version"`
+		Tags    []string  `yaml:"tags"`
+		Ports   []Port    `yaml:"ports"`
+		Updated time.Time
+	}
+	if err := yaml.Unmarshal(got, &settings); err != nil {
+		t.Fatalf("Failed to unmarshal generated YAML back: %v", err)
+	}
+	if settings.Name != `app "main"` || settings.Version != 2 || len(settings.Ports) != 2 || settings.Updated.Year() != 2024 {
+		t.Errorf("Unexpected unmarshalled value: %+v", settings)
+	}
+}
@@ -0,0 +1,98 @@
+// Package mockfactory generates mock values of Go types in memory.
+// Fields are populated according to the same "mock" tags that are used by the CLI:
+//
+//	type User struct {
+//		ID   int    `mock:"min=1000;max=9999"`
+//		Name string `mock:"prefix=user_"`
+//	}
+//
+//	user := mockfactory.New[User]()
+//	users := mockfactory.Many[User](10, mockfactory.WithSeed(42))
+package mockfactory
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/generator"
+	"github.com/maksemen2/mockfactory/internal/parser"
+)
+
+// New returns a value of type T populated with random data.
+// It panics if the type can not be generated, see Fill for an error-returning variant.
+func New[T any](opts ...Option) T {
+	var value T
+	if err := Fill(&value, opts...); err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Many returns n values of type T populated with random data.
+// It panics if the type can not be generated.
+func Many[T any](n int, opts ...Option) []T {
+	values := make([]T, n)
+	targets := make([]reflect.Value, n)
+	for i := range values {
+		targets[i] = reflect.ValueOf(&values[i]).Elem()
+	}
+	if err := fill(targets, newOptions(opts)); err != nil {
+		panic(err)
+	}
+	return values
+}
+
+// Fill populates the value pointed to by ptr with random data.
+func Fill(ptr any, opts ...Option) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New("mockfactory: Fill requires a non-nil pointer")
+	}
+	return fill([]reflect.Value{v.Elem()}, newOptions(opts))
+}
+
+// fill populates every target with a generated value.
+// All targets must be of the same type.
+func fill(targets []reflect.Value, o *options) (err error) {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// generators panic on invalid tags, the library reports them as errors instead
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("mockfactory: %v", r)
+		}
+	}()
+
+	seed := o.config.Generation.RandSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	typ := parser.NewParser(o.config, o.logger).DescribeType(targets[0].Type())
+	builder := generator.NewBuilder(generator.Options{
+		MaxDepth: o.config.Generation.MaxDepth,
+		Nullable: o.config.Generation.Nullable,
+		Locale:   o.config.Generation.Locale,
+		Now:      o.config.Generation.Now,
+	}, generator.NewRandSource(seed), o.logger)
+
+	gen, err := builder.Build(typ, nil)
+	if err != nil {
+		return fmt.Errorf("mockfactory: %w", err)
+	}
+
+	for _, target := range targets {
+		value, err := gen.EvaluateAny()
+		if err != nil {
+			return fmt.Errorf("mockfactory: %w", err)
+		}
+		if err := assign(target, value); err != nil {
+			return fmt.Errorf("mockfactory: %w", err)
+		}
+	}
+	return nil
+}
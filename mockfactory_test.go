@@ -0,0 +1,242 @@
+package mockfactory_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/maksemen2/mockfactory"
+)
+
+type Status string
+
+type Audit struct {
+	CreatedAt time.Time
+	CreatedBy string `mock:"prefix=admin_"`
+}
+
+type Address struct {
+	City string `mock:"len=5"`
+}
+
+type User struct {
+	Audit
+	ID       uuid.UUID
+	Age      int `mock:"min=18;max=99"`
+	Status   Status
+	Address  Address
+	Previous *Address
+	Tags     []string          `mock:"minlen=2;maxlen=2;elem.prefix=tag_"`
+	Scores   map[string]uint16 `mock:"minlen=3;maxlen=3;values.max=100"`
+	Parent   *User
+	Secret   string `mock:"ignore"`
+	internal string
+}
+
+func TestNew(t *testing.T) {
+	user := mockfactory.New[User]()
+
+	if user.ID == uuid.Nil || user.CreatedAt.IsZero() || user.Status == "" {
+		t.Errorf("Fields are not populated: %+v", user)
+	}
+	if user.Age < 18 || user.Age > 99 {
+		t.Errorf("Age %d out of range [18,99]", user.Age)
+	}
+	if !strings.HasPrefix(user.CreatedBy, "admin_") {
+		t.Errorf("Embedded field tags are not applied: %s", user.CreatedBy)
+	}
+	if len(user.Address.City) != 5 || user.Previous == nil || user.Previous.City == "" {
+		t.Errorf("Nested structs are not populated: %+v, %+v", user.Address, user.Previous)
+	}
+	if len(user.Tags) != 2 || !strings.HasPrefix(user.Tags[0], "tag_") {
+		t.Errorf("Unexpected tags: %v", user.Tags)
+	}
+	if len(user.Scores) != 3 {
+		t.Errorf("Unexpected scores: %v", user.Scores)
+	}
+	for _, score := range user.Scores {
+		if score > 100 {
+			t.Errorf("Score %d out of range [0,100]", score)
+		}
+	}
+	depth := 0
+	for parent := user.Parent; parent != nil; parent = parent.Parent {
+		depth++
+	}
+	if depth != 5 {
+		t.Errorf("Recursive field is expanded to depth %d, want the default max depth 5", depth)
+	}
+	if user.Secret != "" || user.internal != "" {
+		t.Errorf("Ignored fields are populated")
+	}
+}
+
+func TestMany(t *testing.T) {
+	// time values without a range tag are relative to the reference time
+	now := time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC)
+	users := mockfactory.Many[User](10, mockfactory.WithSeed(42), mockfactory.WithNow(now))
+	if len(users) != 10 {
+		t.Fatalf("Expected 10 users, got %d", len(users))
+	}
+	if users[0].ID == users[1].ID {
+		t.Errorf("Generated users are identical")
+	}
+
+	again := mockfactory.Many[User](10, mockfactory.WithSeed(42), mockfactory.WithNow(now))
+	if !reflect.DeepEqual(users, again) {
+		t.Errorf("Same seed produced different users")
+	}
+}
+
+func TestFill(t *testing.T) {
+	var address Address
+	if err := mockfactory.Fill(&address); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+	if len(address.City) != 5 {
+		t.Errorf("Unexpected city: %s", address.City)
+	}
+
+	if err := mockfactory.Fill(address); err == nil {
+		t.Errorf("Fill() accepted a non-pointer value")
+	}
+
+	var invalid struct {
+		Name string `mock:"len=abc"`
+	}
+	if err := mockfactory.Fill(&invalid); err == nil {
+		t.Errorf("Fill() accepted an invalid tag")
+	}
+
+	var unsupported struct {
+		Name     string `mock:"len=3"`
+		Events   chan int
+		Callback func()
+		Payload  any
+	}
+	if err := mockfactory.Fill(&unsupported); err != nil {
+		t.Errorf("Fill() error = %v, want unsupported fields to be skipped", err)
+	}
+	if len(unsupported.Name) != 3 || unsupported.Events != nil || unsupported.Callback != nil || unsupported.Payload != nil {
+		t.Errorf("Unexpected values: %+v", unsupported)
+	}
+}
+
+func TestFieldRules(t *testing.T) {
+	type Contact struct {
+		Email string
+		SKU   string
+	}
+
+	contact := mockfactory.New[Contact](mockfactory.WithFieldRules(mockfactory.FieldRule{Name: `^sku$`, Tags: "prefix=SKU-;len=4"}))
+	if !strings.Contains(contact.Email, "@") {
+		t.Errorf("Email is not inferred from the field name: %s", contact.Email)
+	}
+	if !strings.HasPrefix(contact.SKU, "SKU-") || len(contact.SKU) != 8 {
+		t.Errorf("Configured rule is not applied: %s", contact.SKU)
+	}
+
+	contact = mockfactory.New[Contact](mockfactory.WithoutHeuristics())
+	if strings.Contains(contact.Email, "@") {
+		t.Errorf("Heuristics are not disabled: %s", contact.Email)
+	}
+}
+
+func TestBuiltinKinds(t *testing.T) {
+	type Flags struct {
+		Active   bool `mock:"probability=1"`
+		Deleted  bool `mock:"probability=0"`
+		Avatar   []byte
+		Checksum [4]byte `mock:"encoding=hex"`
+		Initial  rune
+		Pointer  uintptr   `mock:"max=10"`
+		Phase    complex64 `mock:"min=-1;max=1"`
+	}
+
+	flags := mockfactory.New[Flags]()
+	if !flags.Active || flags.Deleted {
+		t.Errorf("Probability is not applied: %+v", flags)
+	}
+	if len(flags.Avatar) == 0 || flags.Checksum == [4]byte{} {
+		t.Errorf("Bytes are not generated: %+v", flags)
+	}
+	if flags.Pointer > 10 || real(flags.Phase) < -1 || real(flags.Phase) > 1 || imag(flags.Phase) < -1 || imag(flags.Phase) > 1 {
+		t.Errorf("Range is not applied: %+v", flags)
+	}
+}
+
+func TestWithNow(t *testing.T) {
+	type Session struct {
+		StartedAt time.Time     `mock:"range=past;truncate=second"`
+		Timeout   time.Duration `mock:"min=1m;max=1h"`
+	}
+
+	now := time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC)
+	first := mockfactory.New[Session](mockfactory.WithSeed(42), mockfactory.WithNow(now))
+	second := mockfactory.New[Session](mockfactory.WithSeed(42), mockfactory.WithNow(now))
+	if first != second {
+		t.Errorf("Same seed and reference time should give the same values: %+v and %+v", first, second)
+	}
+	if first.StartedAt.After(now) || first.StartedAt.Before(now.AddDate(-1, 0, 0)) {
+		t.Errorf("StartedAt = %s, want a time within a year before %s", first.StartedAt, now)
+	}
+	if first.Timeout < time.Minute || first.Timeout > time.Hour {
+		t.Errorf("Timeout = %s, want from 1m to 1h", first.Timeout)
+	}
+}
+
+func TestRelatedFields(t *testing.T) {
+	type Money int64
+	type Order struct {
+		Total     Money      `mock:"expr=Price*Quantity"`
+		Price     Money      `mock:"min=100;max=1000"`
+		Quantity  int        `mock:"min=1;max=10"`
+		Title     string     `mock:"template={{.Quantity}} items"`
+		CreatedAt time.Time  `mock:"range=past"`
+		UpdatedAt *time.Time `mock:"range=past;after=CreatedAt"`
+	}
+
+	for _, order := range mockfactory.Many[Order](20, mockfactory.WithSeed(1)) {
+		if order.Total != order.Price*Money(order.Quantity) {
+			t.Errorf("Total = %d, want %d", order.Total, order.Price*Money(order.Quantity))
+		}
+		if want := fmt.Sprintf("%d items", order.Quantity); order.Title != want {
+			t.Errorf("Title = %q, want %q", order.Title, want)
+		}
+		if order.UpdatedAt != nil && !order.UpdatedAt.After(order.CreatedAt) {
+			t.Errorf("UpdatedAt %s is not after CreatedAt %s", order.UpdatedAt, order.CreatedAt)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	type Account struct {
+		ID     int8   `mock:"unique"`
+		Tenant string `mock:"oneof=a,b;unique=tenant_slot"`
+		Slot   int    `mock:"min=1;max=50;unique=tenant_slot"`
+	}
+
+	accounts := mockfactory.Many[Account](100, mockfactory.WithSeed(1))
+	ids := make(map[int8]bool)
+	slots := make(map[string]bool)
+	for _, account := range accounts {
+		slot := fmt.Sprintf("%s/%d", account.Tenant, account.Slot)
+		if ids[account.ID] || slots[slot] {
+			t.Fatalf("Duplicate account %+v", account)
+		}
+		ids[account.ID], slots[slot] = true, true
+	}
+
+	var values []Account
+	err := func() (err error) {
+		defer func() { err, _ = recover().(error) }()
+		values = mockfactory.Many[Account](101, mockfactory.WithSeed(1))
+		return nil
+	}()
+	if err == nil || !strings.Contains(err.Error(), "can not generate a unique value") {
+		t.Errorf("Many() = %d values, error = %v, want exhausted unique values", len(values), err)
+	}
+}
@@ -0,0 +1,110 @@
+package mockfactory
+
+import (
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/maksemen2/mockfactory/internal/config"
+)
+
+// IgnoreStrategy defines which struct fields are generated.
+type IgnoreStrategy = config.FieldIgnoreStrategy
+
+const (
+	IgnoreUntagged = config.IgnoreUntagged // Ignores all fields with no "mock" tag
+	IgnoreWithTag  = config.IgnoreWithTag  // Ignores all fields with "mock:ignore" tag (default)
+	IgnoreAll      = config.IgnoreAll      // IgnoreUntagged && IgnoreWithTag
+	IncludeAll     = config.IncludeAll     // Include all fields
+)
+
+// Option configures value generation.
+type Option func(*options)
+
+type options struct {
+	config *config.Config
+	logger *slog.Logger
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		config: &config.Config{
+			Generation: config.GenerationConfig{MaxDepth: 5},
+			Fields:     config.FieldsConfig{IgnoreStrategy: IgnoreWithTag},
+		},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithSeed makes generated values reproducible.
+// Without a seed, the current time is used.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.config.Generation.RandSeed = seed
+	}
+}
+
+// WithMaxDepth limits the depth of generated nested structs. Defaults to 5.
+func WithMaxDepth(depth int) Option {
+	return func(o *options) {
+		o.config.Generation.MaxDepth = depth
+	}
+}
+
+// WithNullable sets the default probability of nil pointers, from 0 to 1. Defaults to 0.
+func WithNullable(probability float64) Option {
+	return func(o *options) {
+		o.config.Generation.Nullable = probability
+	}
+}
+
+// WithLocale sets the default locale of names, addresses, phones, postcodes and dates,
+// e.g. "de_DE". Defaults to "en_US".
+func WithLocale(locale string) Option {
+	return func(o *options) {
+		o.config.Generation.Locale = locale
+	}
+}
+
+// WithNow pins the reference time of time ranges, like "range=past" or "from=-30d",
+// so that generated times do not depend on the current time.
+func WithNow(now time.Time) Option {
+	return func(o *options) {
+		o.config.Generation.Now = now
+	}
+}
+
+// WithIgnoreStrategy sets which fields are generated. Defaults to IgnoreWithTag.
+func WithIgnoreStrategy(strategy IgnoreStrategy) Option {
+	return func(o *options) {
+		o.config.Fields.IgnoreStrategy = strategy
+	}
+}
+
+// FieldRule chooses mock tags of untagged fields by their names and types.
+type FieldRule = config.FieldRule
+
+// WithFieldRules adds rules for untagged fields, checked before the built-in ones.
+func WithFieldRules(rules ...FieldRule) Option {
+	return func(o *options) {
+		o.config.Fields.Rules = append(o.config.Fields.Rules, rules...)
+	}
+}
+
+// WithoutHeuristics disables choosing mock tags of untagged fields by their names and types.
+func WithoutHeuristics() Option {
+	return func(o *options) {
+		o.config.Fields.DisableHeuristics = true
+	}
+}
+
+// WithLogger sets the logger used during generation. Logging is disabled by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"fmt"
 	"log/slog"
 
 	"github.com/maksemen2/mockfactory/internal/config"
@@ -11,11 +12,16 @@ import (
 func GenerateFromFile(cfg *config.Config, logger *slog.Logger) error {
 
 	p := parser.NewParser(cfg, logger)
-	fields, err := p.ParseFile()
+	fields, err := p.Parse()
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	w := writer.NewJsonWriter(fields, cfg, logger)
+	factory, ok := writer.WriterFactories[cfg.Generation.Format]
+	if !ok {
+		return fmt.Errorf("unsupported output format: %s", cfg.Generation.Format)
+	}
+
+	w := factory.Create(fields, cfg, logger)
 	return w.Write()
 }